@@ -1,3 +1,8 @@
 module github.com/thomjur/verifybibtex
 
 go 1.23.4
+
+require (
+	golang.org/x/text v0.28.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -1,28 +1,316 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/thomjur/verifybibtex/parser"
 )
 
+// The BibTeX file used if no path is given
+const defaultBibTeXFilePath = "bibliography.bib"
+
+// The path argument to read the BibTeX file from stdin and its FilePath
+const (
+	stdinPath     = "-"
+	stdinFilePath = "<stdin>"
+)
+
 func main() {
-	BibTeXFilePath := "bibliography.bib"
-	// Trying to open the file
-	file, err := os.Open(BibTeXFilePath)
+	strict := flag.Bool("strict", false, "exit with a non-zero status on warnings, too")
+	printJSON := flag.Bool("json", false, "print the entries as JSON to stdout (problems are printed to stderr)")
+	check := flag.Bool("check", false, "only list the keys of entries that are not canonically formatted (nothing is rewritten)")
+	stats := flag.Bool("stats", false, "only print statistics about the entries (entry types, DOIs, missing fields, duplicate keys)")
+	auxPath := flag.String("aux", "", "check the citations of a LaTeX .aux file against the entries")
+	profileName := flag.String("profile", "default", "the entry types and fields considered valid: default, bibtex, biblatex, or the path of a JSON or YAML profile file (.json, .yaml, or .yml)")
+	checkLinks := flag.Bool("check-links", false, "request the url, doi, and eprint links of all entries and report dead links (requires network access)")
+	repair := flag.Bool("repair", false, "add the missing closing '}' of the last entry (e.g., of a truncated file) instead of rejecting the entry")
+	strip := flag.String("strip", "", "remove the comma-separated fields (e.g., abstract,annote,file) from all entries before writing or printing them")
+	var write bool
+	flag.BoolVar(&write, "write", false, "reformat the file and write it back in place")
+	flag.BoolVar(&write, "w", false, "shorthand for --write")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: verifybibtex [flags] [file.bib | -]\n\nIf no file is given, %s is used. Use - to read from stdin.\n\nFlags:\n", defaultBibTeXFilePath)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	profile, err := loadProfile(*profileName)
 	if err != nil {
-		fmt.Println("Something went terribly wrong :-(")
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	BibTeXFilePath := defaultBibTeXFilePath
+	if flag.NArg() > 0 {
+		BibTeXFilePath = flag.Arg(0)
+	}
+	// Trying to open the file
+	file := os.Stdin
+	if BibTeXFilePath == stdinPath {
+		BibTeXFilePath = stdinFilePath
+	} else {
+		var err error
+		file, err = os.Open(BibTeXFilePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer file.Close()
 	}
-	defer file.Close()
 
 	//Parse BibTeX file
-	bibtexFile, err := parser.ParseNewBibTeXFile(file)
+	bibtexFile, err := parser.ParseNewBibTeXFileWithOptions(file, parser.ParseOptions{RepairMissingBrace: *repair})
 	if err != nil {
-		fmt.Println("Something went terribly wrong :-(")
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
 	// Don't forget to add filename afterwards
 	bibtexFile.FilePath = BibTeXFilePath
+	if *strip != "" {
+		bibtexFile.StripFields(strings.Split(*strip, ",")...)
+	}
+
+	// Only check the formatting, like gofmt -l
+	if *check {
+		unformattedKeys := bibtexFile.UnformattedKeys(parser.DefaultFormatOptions)
+		for _, key := range unformattedKeys {
+			fmt.Printf("%s: %s\n", bibtexFile.FilePath, key)
+		}
+		for _, err := range bibtexFile.Errors {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", bibtexFile.FilePath, err)
+		}
+		if len(unformattedKeys) > 0 || len(bibtexFile.Errors) > 0 {
+			file.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Only print an overview of the entries
+	if *stats {
+		printStats(os.Stdout, bibtexFile, profile)
+		return
+	}
+
+	// Reformat the file in place, but never drop entries that could not be parsed
+	if write {
+		if BibTeXFilePath == stdinFilePath {
+			fmt.Fprintln(os.Stderr, "cannot write the formatted output back to stdin")
+			os.Exit(1)
+		}
+		for _, err := range bibtexFile.Errors {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", bibtexFile.FilePath, err)
+		}
+		if len(bibtexFile.Errors) > 0 {
+			fmt.Fprintf(os.Stderr, "%s: not written because of parsing errors\n", bibtexFile.FilePath)
+			file.Close()
+			os.Exit(1)
+		}
+		// Never drop text that cannot be written back, e.g., % comments between the entries
+		lossyWrites := bibtexFile.CheckWriteBack()
+		for _, err := range lossyWrites {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", bibtexFile.FilePath, err)
+		}
+		if len(lossyWrites) > 0 {
+			fmt.Fprintf(os.Stderr, "%s: not written because the content above would be lost or changed\n", bibtexFile.FilePath)
+			file.Close()
+			os.Exit(1)
+		}
+		file.Close()
+		if err := writeFileAtomically(BibTeXFilePath, bibtexFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Report all parsing and validation problems, keep stdout clean for JSON
+	reportOutput := io.Writer(os.Stdout)
+	colored := isTerminal(os.Stdout)
+	if *printJSON {
+		data, err := bibtexFile.MarshalJSON()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			file.Close()
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		reportOutput = os.Stderr
+		colored = isTerminal(os.Stderr)
+	}
+	// Cross-check the citations of the LaTeX document
+	var additionalProblems []error
+	if *auxPath != "" {
+		citedKeys, err := readAuxCitations(*auxPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			file.Close()
+			os.Exit(1)
+		}
+		additionalProblems = bibtexFile.CheckCitations(citedKeys)
+	}
+	// Only request the links if asked to, the validation is offline otherwise
+	if *checkLinks {
+		for _, result := range parser.CheckLinks(context.Background(), bibtexFile.Links(), 0) {
+			additionalProblems = append(additionalProblems, result.Errors()...)
+		}
+	}
+	errorCount, warningCount := report(reportOutput, colored, bibtexFile, parser.ValidateOptions{Profile: profile}, additionalProblems)
+	disabled := ""
+	if len(bibtexFile.DisabledEntries) > 0 {
+		disabled = fmt.Sprintf(" (%d disabled in @comment blocks)", len(bibtexFile.DisabledEntries))
+	}
+	fmt.Fprintf(reportOutput, "%s: %d entries%s, %d errors, %d warnings\n", bibtexFile.FilePath, len(bibtexFile.Entries), disabled, errorCount, warningCount)
+	if errorCount > 0 || (*strict && warningCount > 0) {
+		file.Close()
+		os.Exit(1)
+	}
+}
+
+// report prints all parsing errors and validation problems of the BibTeX file and the
+// given additional problems to w, colored by their severity if colored is set, and returns
+// the number of errors and warnings (see parser.Diagnostic). The xdata fields and crossrefs are resolved and entry sets are
+// checked before the entries are validated with opts. Authors that are not written in the
+// form of most others and text between the entries are reported as well.
+func report(w io.Writer, colored bool, bibtexFile *parser.BibTeXFile, opts parser.ValidateOptions, additionalProblems []error) (errorCount, warningCount int) {
+	problems := append([]error{}, bibtexFile.Errors...)
+	problems = append(problems, bibtexFile.ResolveXData()...)
+	problems = append(problems, bibtexFile.ResolveCrossrefs()...)
+	problems = append(problems, bibtexFile.CheckEntrySets()...)
+	problems = append(problems, bibtexFile.CheckNameForms()...)
+	problems = append(problems, bibtexFile.CheckStrayText()...)
+	for _, entry := range bibtexFile.Entries {
+		problems = append(problems, entry.ValidateWithOptions(context.Background(), opts)...)
+	}
+	problems = append(problems, additionalProblems...)
+	for _, diagnostic := range bibtexFile.DiagnosticsOf(problems) {
+		color, reset := severityColors[diagnostic.Severity], colorReset
+		if !colored {
+			color, reset = "", ""
+		}
+		fmt.Fprintf(w, "%s: %s%s%s\n", bibtexFile.FilePath, color, diagnostic, reset)
+		switch diagnostic.Severity {
+		case parser.SeverityError:
+			errorCount++
+		case parser.SeverityWarning:
+			warningCount++
+		}
+	}
+	return errorCount, warningCount
+}
+
+// The ANSI escape codes to color the diagnostics by their severity
+var severityColors = map[parser.Severity]string{
+	parser.SeverityError:   "\033[31m",
+	parser.SeverityWarning: "\033[33m",
+	parser.SeverityInfo:    "\033[36m",
+}
+
+const colorReset = "\033[0m"
 
+// isTerminal reports whether the file is a terminal (and not a pipe or a regular file) and
+// colors are not disabled by the NO_COLOR environment variable.
+func isTerminal(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printStats prints the statistics of the BibTeX file (see parser.BibTeXFile.Stats) to w,
+// with the entry types in alphabetical order. The required fields are checked with the given
+// profile. The xdata fields and crossrefs are resolved first.
+func printStats(w io.Writer, bibtexFile *parser.BibTeXFile, profile *parser.ValidationProfile) {
+	bibtexFile.ResolveXData()
+	bibtexFile.ResolveCrossrefs()
+	stats := bibtexFile.Stats(profile)
+	fmt.Fprintf(w, "%s: %d entries\n", bibtexFile.FilePath, stats.Entries)
+	entryTypes := make([]string, 0, len(stats.EntryTypes))
+	for entryType := range stats.EntryTypes {
+		entryTypes = append(entryTypes, entryType)
+	}
+	sort.Strings(entryTypes)
+	for _, entryType := range entryTypes {
+		fmt.Fprintf(w, "  %-24s %d\n", "@"+entryType, stats.EntryTypes[entryType])
+	}
+	fmt.Fprintf(w, "  %-24s %d\n", "with DOI", stats.WithDOI)
+	fmt.Fprintf(w, "  %-24s %d\n", "missing required fields", stats.MissingRequiredFields)
+	fmt.Fprintf(w, "  %-24s %d\n", "duplicate keys", stats.DuplicateKeys)
+}
+
+// loadProfile returns the built-in profile with the given name (see parser.LookupProfile)
+// or reads the profile from the JSON or YAML file at this path (see parser.LoadValidationProfile).
+// Paths with other extensions are rejected.
+func loadProfile(nameOrPath string) (*parser.ValidationProfile, error) {
+	if profile, ok := parser.LookupProfile(nameOrPath); ok {
+		return profile, nil
+	}
+	switch strings.ToLower(filepath.Ext(nameOrPath)) {
+	case ".json", ".yaml", ".yml":
+	default:
+		return nil, fmt.Errorf("unknown profile '%s' (expected default, bibtex, biblatex, or the path of a .json, .yaml, or .yml file)", nameOrPath)
+	}
+	profileFile, err := os.Open(nameOrPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("unknown profile '%s'", nameOrPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer profileFile.Close()
+	profile, err := parser.LoadValidationProfile(profileFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", nameOrPath, err)
+	}
+	return profile, nil
+}
+
+// readAuxCitations returns the cited keys of the LaTeX .aux file at path.
+func readAuxCitations(path string) ([]string, error) {
+	auxFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer auxFile.Close()
+	return parser.ParseAuxCitations(auxFile)
+}
+
+// writeFileAtomically writes the formatted BibTeX file to a temporary file next to path and
+// renames it to path afterwards, so an interrupted run never leaves a truncated file behind.
+// The permissions of the original file are kept.
+func writeFileAtomically(path string, bibtexFile *parser.BibTeXFile) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Remove the temporary file if anything fails before the rename
+	defer os.Remove(tempFile.Name())
+	if _, err := bibtexFile.WriteTo(tempFile); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tempFile.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), path)
 }
@@ -0,0 +1,128 @@
+// Unit-tests for main.go
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thomjur/verifybibtex/parser"
+)
+
+// TestMain runs the CLI instead of the tests if the test binary is started by runCLI.
+func TestMain(m *testing.M) {
+	if os.Getenv("VERIFYBIBTEX_RUN_CLI") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runCLI runs the CLI with the given arguments and returns its stderr and exit error.
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "VERIFYBIBTEX_RUN_CLI=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stderr.String(), err
+}
+
+// writeBibTeXFile writes the BibTeX text to a new file in a temporary directory.
+func writeBibTeXFile(t *testing.T, bib string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "references.bib")
+	if err := os.WriteFile(path, []byte(bib), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	bib := `@string{pub = {ACM Press}}
+@book{knuth1997art,
+  title = {The Art of Computer Programming},
+  author = {Donald E. Knuth},
+  publisher = pub # " (Reprint)",
+  year = 1997,
+  month = jan
+}
+@comment{@misc{disabled, title = {Disabled}}}
+@misc{last, title = {Last}}
+`
+	path := writeBibTeXFile(t, bib)
+
+	// Case 1: The macro references are written back instead of their values
+	if stderr, err := runCLI(t, "-w", path); err != nil {
+		t.Fatalf("Expected no error, but got '%v' (%s)", err, stderr)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"@string{pub = {ACM Press}}", `publisher = pub # " (Reprint)"`, "month = jan", "year = {1997}"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected '%s' in '%s'", expected, data)
+		}
+	}
+	// The disabled entry stays between the two entries
+	knuth, disabled, last := strings.Index(string(data), "@book{knuth1997art"), strings.Index(string(data), "@comment{@misc{disabled"), strings.Index(string(data), "@misc{last")
+	if knuth == -1 || !(knuth < disabled && disabled < last) {
+		t.Errorf("Expected the @comment between the entries, but got '%s'", data)
+	}
+
+	// Case 2: The written file has the same entries and values
+	original, _ := parser.ParseString(bib)
+	written, _ := parser.ParseString(string(data))
+	if len(written.Entries) != 2 || written.Entries[0].Key != "knuth1997art" || len(written.DisabledEntries) != 1 {
+		t.Fatalf("Expected the entries 'knuth1997art' and 'last' and one disabled entry, but got '%#v'", written.Entries)
+	}
+	for fieldName, value := range original.Entries[0].Fields {
+		if written.Entries[0].Fields[fieldName] != value {
+			t.Errorf("Expected '%s', but got '%s' for field '%s'", value, written.Entries[0].Fields[fieldName], fieldName)
+		}
+	}
+
+	// Case 3: Writing the file again does not change it
+	if stderr, err := runCLI(t, "-w", path); err != nil {
+		t.Fatalf("Expected no error, but got '%v' (%s)", err, stderr)
+	}
+	if rewritten, _ := os.ReadFile(path); !bytes.Equal(data, rewritten) {
+		t.Errorf("Expected '%s', but got '%s'", data, rewritten)
+	}
+}
+
+func TestWriteRefusesLossyWrites(t *testing.T) {
+	// Case 1: Content that cannot be written back is reported and the file is not changed
+	cases := map[string]string{
+		"% comment":   "% Exported by my reference manager\n@misc{a, title = {A}}\n",
+		"stray text":  "@misc{a, title = {A}}\nleftover}\n@misc{b, title = {B}}\n",
+		"parse":       "@misc{a, title = {A} year = {2024}}\n",
+		"duplicate":   "@misc{a, title = {A1}, title = {A2}}\n",
+		"field names": "@misc{a, my field = {x}}\n",
+	}
+	expectedReasons := map[string]string{
+		"% comment":   "the % comment would be lost",
+		"stray text":  "the text outside of entries would be lost",
+		"parse":       "despite a syntax problem",
+		"duplicate":   "all but the last value of field 'title' would be lost",
+		"field names": "the invalid field name 'my field'",
+	}
+	for name, bib := range cases {
+		path := writeBibTeXFile(t, bib)
+		stderr, err := runCLI(t, "-w", path)
+		if err == nil {
+			t.Errorf("Expected an error for %s, but got none", name)
+		}
+		if !strings.Contains(stderr, expectedReasons[name]) || !strings.Contains(stderr, "not written") {
+			t.Errorf("Expected '%s' in '%s'", expectedReasons[name], stderr)
+		}
+		if data, _ := os.ReadFile(path); string(data) != bib {
+			t.Errorf("Expected '%s', but got '%s'", bib, data)
+		}
+	}
+}
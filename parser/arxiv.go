@@ -0,0 +1,85 @@
+// The arxiv.go source file includes functions for entries of arXiv preprints
+//
+// ArxivURL: returns the URL of the abstract page of an arXiv eprint
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Define errors
+type ErrInvalidArxivID struct {
+	Key   string
+	Value string
+}
+
+func (e *ErrInvalidArxivID) Error() string {
+	return fmt.Sprintf("Error validating BibTeX entry '%s': invalid arXiv identifier '%s' (expected, e.g., 2301.01234 or math/0601001)", e.Key, e.Value)
+}
+
+type ErrMissingPrimaryClass struct {
+	Key string
+}
+
+func (e *ErrMissingPrimaryClass) Error() string {
+	return fmt.Sprintf("Warning validating BibTeX entry '%s': arXiv eprint without primaryclass (e.g., cs.CL)", e.Key)
+}
+
+// Regex to match arXiv identifiers in the new form YYMM.NNNNN (NNNN before 2015) and in the
+// old form archive/YYMMNNN (e.g., math/0601001 or math.AG/0601001), with an optional version
+// like v2 and an optional arXiv: prefix
+var regexArxivID = regexp.MustCompile(`^(?i:arxiv:)?(?:\d{2}(?:0[1-9]|1[0-2])\.\d{4,5}|[a-z]+(?:-[a-z]+)*(?:\.[A-Z]{2})?/\d{2}(?:0[1-9]|1[0-2])\d{3})(?:v\d+)?$`)
+
+// isArxiv reports whether the eprint of the entry is an arXiv eprint, i.e., the
+// archiveprefix (or the biblatex eprinttype) is arXiv, ignoring the case.
+func (e *Entry) isArxiv() bool {
+	for _, fieldName := range []string{"archiveprefix", "eprinttype"} {
+		if strings.EqualFold(strings.TrimSpace(stripBraces(e.Fields[fieldName])), "arxiv") {
+			return true
+		}
+	}
+	return false
+}
+
+// arxivID returns the trimmed eprint of the entry without an arXiv: prefix.
+func (e *Entry) arxivID() string {
+	id := strings.TrimSpace(e.Fields["eprint"])
+	if len(id) > len("arxiv:") && strings.EqualFold(id[:len("arxiv:")], "arxiv:") {
+		id = id[len("arxiv:"):]
+	}
+	return id
+}
+
+// checkArxiv checks the eprint of arXiv entries (see isArxiv): it returns an
+// ErrInvalidArxivID if the eprint is not an arXiv identifier, and an ErrMissingPrimaryClass
+// warning if the entry has no primary category in primaryclass (or the biblatex eprintclass).
+// Other entries are not checked.
+func (e *Entry) checkArxiv() []error {
+	if !e.isArxiv() {
+		return nil
+	}
+	var errs []error
+	if eprint := strings.TrimSpace(e.Fields["eprint"]); !regexArxivID.MatchString(eprint) {
+		errs = append(errs, &ErrInvalidArxivID{Key: e.Key, Value: eprint})
+	}
+	if !e.hasAnyNonEmptyField([]string{"primaryclass", "eprintclass"}) {
+		errs = append(errs, &ErrMissingPrimaryClass{Key: e.Key})
+	}
+	return errs
+}
+
+// ArxivURL returns the URL of the abstract page of the arXiv eprint of the entry, e.g.,
+// https://arxiv.org/abs/2301.01234 for eprint = {2301.01234} and archiveprefix = {arXiv}
+// (or the biblatex eprinttype = {arXiv}). ok is false if the entry is no arXiv eprint or the
+// eprint is not a valid arXiv identifier (see Validate).
+func (e *Entry) ArxivURL() (url string, ok bool) {
+	if !e.isArxiv() || !regexArxivID.MatchString(strings.TrimSpace(e.Fields["eprint"])) {
+		return "", false
+	}
+	return eprintURLs["arxiv"] + e.arxivID(), true
+}
@@ -0,0 +1,68 @@
+// Unit-tests for arxiv.go
+package parser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestValidateArxiv(t *testing.T) {
+	// Case 1: New and old arXiv identifiers with their primary class
+	for _, eprint := range []string{"2301.01234", "0704.0001", "2301.01234v2", "arXiv:2301.01234", "math/0601001", "math.AG/0601001v1", "hep-th/9901001"} {
+		entry, _ := ParseNewEntry(`@misc{doe2023, title = {T}, eprint = {` + eprint + `}, archivePrefix = {arXiv}, primaryClass = {cs.CL}}`)
+		if errs := entry.Validate(); len(errs) != 0 {
+			t.Errorf("Expected no errors for '%s', but got '%#v'", eprint, errs)
+		}
+	}
+
+	// Case 2: Invalid identifiers and missing primary classes
+	for _, eprint := range []string{"", "2313.01234", "2301.123", "2301.012345", "math/06010", "Math/0601001", "10.1000/182"} {
+		entry, _ := ParseNewEntry(`@misc{doe2023, title = {T}, eprint = {` + eprint + `}, archiveprefix = {arXiv}}`)
+		expected := []error{&ErrInvalidArxivID{Key: "doe2023", Value: eprint}, &ErrMissingPrimaryClass{Key: "doe2023"}}
+		errs := entry.Validate()
+		if !reflect.DeepEqual(expected, errs) {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+		}
+		if len(errs) == 2 && (IsWarning(errs[0]) || !IsWarning(errs[1])) {
+			t.Errorf("Expected only '%#v' to be a warning", errs[1])
+		}
+	}
+
+	// Case 3: The biblatex fields, and eprints of other archives are not checked
+	entry3, _ := ParseNewEntry(`@online{doe2023, author = {Doe, Jane}, title = {T}, date = {2023}, eprint = {2301.01234}, eprinttype = {arxiv}, eprintclass = {cs.CL}}`)
+	if errs := entry3.ValidateWithOptions(context.Background(), ValidateOptions{Profile: Biblatex}); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+	entry4, _ := ParseNewEntry(`@misc{doe2023, title = {T}, eprint = {10.1000/182}, eprinttype = {hdl}}`)
+	if errs := entry4.Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+}
+
+func TestArxivURL(t *testing.T) {
+	// Case 1: URLs of the abstract pages
+	cases := map[string]string{
+		`@misc{a, eprint = {2301.01234}, archiveprefix = {arXiv}}`:           "https://arxiv.org/abs/2301.01234",
+		`@misc{a, eprint = { arXiv:2301.01234v2 }, archiveprefix = {ARXIV}}`: "https://arxiv.org/abs/2301.01234v2",
+		`@misc{a, eprint = {math.AG/0601001}, eprinttype = {{arXiv}}}`:       "https://arxiv.org/abs/math.AG/0601001",
+	}
+	for bib, expected := range cases {
+		entry, _ := ParseNewEntry(bib)
+		if url, ok := entry.ArxivURL(); !ok || url != expected {
+			t.Errorf("Expected '%#v', but got '%#v' (%v)", expected, url, ok)
+		}
+	}
+
+	// Case 2: No arXiv eprints or invalid identifiers
+	for _, bib := range []string{
+		`@misc{a, eprint = {2301.01234}}`,
+		`@misc{a, eprint = {12345}, eprinttype = {pubmed}}`,
+		`@misc{a, eprint = {2301}, archiveprefix = {arXiv}}`,
+	} {
+		entry, _ := ParseNewEntry(bib)
+		if url, ok := entry.ArxivURL(); ok {
+			t.Errorf("Expected no URL, but got '%#v'", url)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// The bibtexfile.go source file includes methods to access the entries of a BibTeXFile
+//
+// LookupByKey: finds an entry by its BibTeX key
+// DuplicateKeys: reports keys shared by several entries
+// FilterByType: selects entries of certain entry types
+// All: iterates over all entries
+// OfType: iterates over the entries of an entry type
+// StripFields: removes fields from all entries
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"iter"
+	"strings"
+)
+
+// EmptyKey is the sentinel used by DuplicateKeys to group entries without a key.
+const EmptyKey = "<empty>"
+
+// LookupByKey returns the entry with the given key.
+// BibTeX keys are case-sensitive, so Smith2020 and smith2020 are distinct keys.
+// If several entries share the same key, the first one is returned (as LaTeX would cite it).
+// The lookup index is built on the first call and cached. If Entries is changed,
+// use SetEntries to invalidate the cache.
+func (f *BibTeXFile) LookupByKey(key string) (*Entry, bool) {
+	if f.keyIndex == nil {
+		f.keyIndex = make(map[string]*Entry, len(f.Entries))
+		for _, entry := range f.Entries {
+			if _, ok := f.keyIndex[entry.Key]; !ok {
+				f.keyIndex[entry.Key] = entry
+			}
+		}
+	}
+	entry, ok := f.keyIndex[key]
+	return entry, ok
+}
+
+// SetEntries replaces the entries of the BibTeXFile and invalidates the lookup index.
+func (f *BibTeXFile) SetEntries(entries []*Entry) {
+	f.Entries = entries
+	f.keyIndex = nil
+}
+
+// DuplicateKeys returns all keys that are shared by more than one entry, mapped to
+// the indices of these entries in Entries. Keys that appear only once are not included.
+// Entries with an empty (or unparsable) key are always grouped under EmptyKey,
+// even if there is only one of them.
+func (f *BibTeXFile) DuplicateKeys() map[string][]int {
+	indices := make(map[string][]int)
+	for i, entry := range f.Entries {
+		key := entry.Key
+		if key == "" {
+			key = EmptyKey
+		}
+		indices[key] = append(indices[key], i)
+	}
+	duplicates := make(map[string][]int)
+	for key, entryIndices := range indices {
+		if len(entryIndices) > 1 || key == EmptyKey {
+			duplicates[key] = entryIndices
+		}
+	}
+	return duplicates
+}
+
+// FilterByType returns all entries whose entry type matches one of the given types.
+// As BibTeX entry types, the types are compared case-insensitively.
+// The entries keep their order from Entries.
+func (f *BibTeXFile) FilterByType(types ...string) []*Entry {
+	var filtered []*Entry
+	for _, entry := range f.Entries {
+		for _, entryType := range types {
+			if strings.EqualFold(entry.EntryType, entryType) {
+				filtered = append(filtered, entry)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// All returns an iterator over the indices and entries of Entries:
+//
+//	for i, entry := range bibtexFile.All() {
+//		...
+//	}
+func (f *BibTeXFile) All() iter.Seq2[int, *Entry] {
+	return func(yield func(int, *Entry) bool) {
+		for i, entry := range f.Entries {
+			if !yield(i, entry) {
+				return
+			}
+		}
+	}
+}
+
+// OfType returns an iterator over all entries of the given entry type like FilterByType,
+// but without collecting the entries in a new slice.
+func (f *BibTeXFile) OfType(entryType string) iter.Seq[*Entry] {
+	return func(yield func(*Entry) bool) {
+		for _, entry := range f.Entries {
+			if strings.EqualFold(entry.EntryType, entryType) && !yield(entry) {
+				return
+			}
+		}
+	}
+}
+
+// StripFields removes the fields with the given names from all entries in place (see
+// DeleteField), e.g., abstract or annote to share a slimmer BibTeX file. As field names,
+// the names are compared case-insensitively, so Abstract and abstract are both removed.
+// It returns the number of removed fields.
+func (f *BibTeXFile) StripFields(names ...string) int {
+	removed := 0
+	for _, entry := range f.Entries {
+		for _, name := range names {
+			if entry.DeleteField(name) {
+				removed++
+			}
+		}
+	}
+	return removed
+}
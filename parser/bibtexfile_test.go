@@ -0,0 +1,174 @@
+// Unit-tests for bibtexfile.go
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLookupByKey(t *testing.T) {
+	bib := `
+@book{Smith2020,
+  author       = {John Smith},
+  title        = {Upper Case},
+  publisher    = {Springer},
+  year         = {2020}
+}
+
+@book{smith2020,
+  author       = {John Smith},
+  title        = {Lower Case},
+  publisher    = {Springer},
+  year         = {2020}
+}
+`
+	parsedBibTeXFile, _ := ParseNewBibTeXFile(strings.NewReader(bib))
+
+	// Case 1: Keys are case-sensitive
+	entry1, ok1 := parsedBibTeXFile.LookupByKey("Smith2020")
+	if !ok1 || entry1.Fields["title"] != "Upper Case" {
+		t.Errorf("Expected '%#v', but got '%#v'", "Upper Case", entry1)
+	}
+	entry2, ok2 := parsedBibTeXFile.LookupByKey("smith2020")
+	if !ok2 || entry2.Fields["title"] != "Lower Case" {
+		t.Errorf("Expected '%#v', but got '%#v'", "Lower Case", entry2)
+	}
+
+	// Case 2: Unknown key
+	if _, ok := parsedBibTeXFile.LookupByKey("SMITH2020"); ok {
+		t.Errorf("Expected no entry for key '%s'", "SMITH2020")
+	}
+
+	// Case 3: SetEntries invalidates the cached index
+	newEntry, _ := ParseNewEntry(`@misc{doe2021, title = {New}}`)
+	parsedBibTeXFile.SetEntries([]*Entry{newEntry})
+	if _, ok := parsedBibTeXFile.LookupByKey("Smith2020"); ok {
+		t.Errorf("Expected no entry for key '%s' after SetEntries", "Smith2020")
+	}
+	entry3, ok3 := parsedBibTeXFile.LookupByKey("doe2021")
+	if !ok3 || entry3 != newEntry {
+		t.Errorf("Expected '%#v', but got '%#v'", newEntry, entry3)
+	}
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	bib := `
+@misc{doe2021, title = {First}}
+@misc{smith2020, title = {Unique}}
+@misc{doe2021, title = {Second}}
+@misc{, title = {No key}}
+@misc{doe2021, title = {Third}}
+`
+	parsedBibTeXFile, _ := ParseNewBibTeXFile(strings.NewReader(bib))
+	expected := map[string][]int{"doe2021": {0, 2, 4}, EmptyKey: {3}}
+	duplicates := parsedBibTeXFile.DuplicateKeys()
+	if !reflect.DeepEqual(expected, duplicates) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, duplicates)
+	}
+
+	// No duplicates
+	parsedBibTeXFile.SetEntries(parsedBibTeXFile.Entries[:2])
+	duplicates2 := parsedBibTeXFile.DuplicateKeys()
+	if len(duplicates2) != 0 {
+		t.Errorf("Expected no duplicates, but got '%#v'", duplicates2)
+	}
+}
+
+func TestFilterByType(t *testing.T) {
+	bib := `
+@book{knuth1997art, title = {The Art of Computer Programming}}
+@Article{smith2021ai, title = {Advancements in AI}}
+@inproceedings{doe2022quantum, title = {Exploring Quantum Computing}}
+@ARTICLE{roe2023, title = {Another Article}}
+`
+	parsedBibTeXFile, _ := ParseNewBibTeXFile(strings.NewReader(bib))
+
+	// Case 1: Single type, case-insensitive
+	var keys1 []string
+	for _, entry := range parsedBibTeXFile.FilterByType("ARTICLE") {
+		keys1 = append(keys1, entry.Key)
+	}
+	expected1 := []string{"smith2021ai", "roe2023"}
+	if !reflect.DeepEqual(expected1, keys1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, keys1)
+	}
+
+	// Case 2: Several types
+	var keys2 []string
+	for _, entry := range parsedBibTeXFile.FilterByType("book", "inproceedings") {
+		keys2 = append(keys2, entry.Key)
+	}
+	expected2 := []string{"knuth1997art", "doe2022quantum"}
+	if !reflect.DeepEqual(expected2, keys2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, keys2)
+	}
+
+	// Case 3: No matching type
+	if filtered := parsedBibTeXFile.FilterByType("phdthesis"); len(filtered) != 0 {
+		t.Errorf("Expected no entries, but got '%#v'", filtered)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	bibtexFile, _ := ParseNewBibTeXFile(strings.NewReader(`@misc{a, title = {A}}
+@Book{b, title = {B}}
+@misc{c, title = {C}}
+@book{d, title = {D}}`))
+	// Case 1: All entries with their indices
+	var indices []int
+	var keys []string
+	for i, entry := range bibtexFile.All() {
+		indices = append(indices, i)
+		keys = append(keys, entry.Key)
+	}
+	if !reflect.DeepEqual([]int{0, 1, 2, 3}, indices) || !reflect.DeepEqual([]string{"a", "b", "c", "d"}, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", []string{"a", "b", "c", "d"}, keys)
+	}
+
+	// Case 2: Entries of one type
+	var bookKeys []string
+	for entry := range bibtexFile.OfType("BOOK") {
+		bookKeys = append(bookKeys, entry.Key)
+	}
+	if !reflect.DeepEqual([]string{"b", "d"}, bookKeys) {
+		t.Errorf("Expected '%#v', but got '%#v'", []string{"b", "d"}, bookKeys)
+	}
+
+	// Case 3: Break stops the iteration
+	var firstKeys []string
+	for _, entry := range bibtexFile.All() {
+		if entry.Key == "c" {
+			break
+		}
+		firstKeys = append(firstKeys, entry.Key)
+	}
+	if !reflect.DeepEqual([]string{"a", "b"}, firstKeys) {
+		t.Errorf("Expected '%#v', but got '%#v'", []string{"a", "b"}, firstKeys)
+	}
+}
+
+func TestStripFields(t *testing.T) {
+	bibtexFile, _ := ParseString(`@article{a, title = {A}, Abstract = {Long}, ANNOTE = {Notes}, year = 2020}
+@misc{b, title = {B}, file = {b.pdf}}
+@misc{c, title = {C}}`)
+
+	// Case 1: The fields are removed regardless of the case of their names
+	if removed := bibtexFile.StripFields("abstract", "Annote", " file "); removed != 3 {
+		t.Errorf("Expected '%#v', but got '%#v'", 3, removed)
+	}
+	expected := []map[string]string{{"title": "A", "year": "2020"}, {"title": "B"}, {"title": "C"}}
+	for i, entry := range bibtexFile.Entries {
+		if !reflect.DeepEqual(expected[i], entry.Fields) {
+			t.Errorf("Expected '%#v', but got '%#v'", expected[i], entry.Fields)
+		}
+	}
+	if _, ok := bibtexFile.Entries[0].RawFieldNames["abstract"]; ok {
+		t.Errorf("Expected no raw field name, but got '%#v'", bibtexFile.Entries[0].RawFieldNames)
+	}
+
+	// Case 2: Nothing left to remove
+	if removed := bibtexFile.StripFields("abstract"); removed != 0 {
+		t.Errorf("Expected '%#v', but got '%#v'", 0, removed)
+	}
+}
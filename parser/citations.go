@@ -0,0 +1,111 @@
+// The citations.go source file includes functions to compare the entries of a BibTeX file with the citations of a document
+//
+// CrossCheckCitations: reports cited keys without entry and entries that are never cited
+// CheckCitations: returns the results of CrossCheckCitations as errors
+// ParseAuxCitations: extracts the cited keys from a LaTeX .aux file
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Define errors
+type ErrMissingCitation struct {
+	Key string
+}
+
+type ErrUnusedEntry struct {
+	Key string
+}
+
+func (e *ErrMissingCitation) Error() string {
+	return fmt.Sprintf("Error checking citations: key '%s' is cited, but there is no entry", e.Key)
+}
+
+func (e *ErrUnusedEntry) Error() string {
+	return fmt.Sprintf("Warning checking citations: entry '%s' is never cited", e.Key)
+}
+
+// Regex to find the keys of \citation{...} commands in .aux files
+var regexAuxCitation = regexp.MustCompile(`\\citation\{([^}]*)\}`)
+
+// CrossCheckCitations compares the keys cited in a document (e.g., by \cite) with the entries.
+// It returns the cited keys that have no entry (missing) in the order of their first
+// citation, and the keys of the entries that are never cited (unused) in the order of
+// Entries; @xdata entries are never cited, so they are not unused. Both lists contain every
+// key only once. As in LaTeX, keys are case-sensitive,
+// and the key * (as in \nocite{*}) cites all entries.
+func (f *BibTeXFile) CrossCheckCitations(keys []string) (missing []string, unused []string) {
+	cited := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if cited[key] {
+			continue
+		}
+		cited[key] = true
+		if _, ok := f.LookupByKey(key); !ok && key != "*" {
+			missing = append(missing, key)
+		}
+	}
+	if cited["*"] {
+		return missing, nil
+	}
+	reported := make(map[string]bool)
+	for _, entry := range f.Entries {
+		if !cited[entry.Key] && !reported[entry.Key] && entry.isPrinted() {
+			unused = append(unused, entry.Key)
+			reported[entry.Key] = true
+		}
+	}
+	return missing, unused
+}
+
+// CheckCitations cross-checks the cited keys like CrossCheckCitations and returns an
+// ErrMissingCitation for every missing key and an ErrUnusedEntry warning (see IsWarning)
+// for every entry that is never cited.
+func (f *BibTeXFile) CheckCitations(keys []string) []error {
+	missing, unused := f.CrossCheckCitations(keys)
+	var errs []error
+	for _, key := range missing {
+		errs = append(errs, &ErrMissingCitation{Key: key})
+	}
+	for _, key := range unused {
+		errs = append(errs, &ErrUnusedEntry{Key: key})
+	}
+	return errs
+}
+
+// ParseAuxCitations extracts the cited keys from the \citation{...} commands of a LaTeX
+// .aux file, which LaTeX writes for every \cite and \nocite. A command can contain
+// several comma-separated keys (e.g., \citation{a,b}). The keys are returned once each,
+// in the order of their first citation; \citation{*} (from \nocite{*}) is returned as
+// the key *, which cites all entries in CrossCheckCitations.
+// The .aux files of included files (\@input{...}) are not followed.
+func ParseAuxCitations(r io.Reader) ([]string, error) {
+	var keys []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	// Lines of .aux files can be very long
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		for _, match := range regexAuxCitation.FindAllStringSubmatch(scanner.Text(), -1) {
+			for _, key := range strings.Split(match[1], ",") {
+				key = strings.TrimSpace(key)
+				if key != "" && !seen[key] {
+					keys = append(keys, key)
+					seen[key] = true
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,64 @@
+// Unit-tests for citations.go
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCrossCheckCitations(t *testing.T) {
+	file, _ := ParseString(`@misc{a, title = {A}}
+@misc{b, title = {B}}
+@misc{c, title = {C}}
+@misc{c, title = {C2}}`)
+
+	// Case 1: Missing and unused keys
+	missing1, unused1 := file.CrossCheckCitations([]string{"b", "x", "A", "x", "b"})
+	if expected := []string{"x", "A"}; !reflect.DeepEqual(expected, missing1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, missing1)
+	}
+	if expected := []string{"a", "c"}; !reflect.DeepEqual(expected, unused1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, unused1)
+	}
+
+	// Case 2: \nocite{*} cites all entries
+	missing2, unused2 := file.CrossCheckCitations([]string{"*", "y"})
+	if !reflect.DeepEqual([]string{"y"}, missing2) || unused2 != nil {
+		t.Errorf("Expected '%#v' and no unused keys, but got '%#v' and '%#v'", []string{"y"}, missing2, unused2)
+	}
+}
+
+func TestCheckCitations(t *testing.T) {
+	// Case 1: Missing keys are errors, unused entries are warnings
+	file, _ := ParseString(`@misc{a, title = {A}} @misc{b, title = {B}}`)
+	expected := []error{&ErrMissingCitation{Key: "x"}, &ErrUnusedEntry{Key: "b"}}
+	errs := file.CheckCitations([]string{"a", "x"})
+	if !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+	if len(errs) == 2 && (IsWarning(errs[0]) || !IsWarning(errs[1])) {
+		t.Errorf("Expected only '%#v' to be a warning", errs[1])
+	}
+}
+
+func TestParseAuxCitations(t *testing.T) {
+	aux := `\relax
+\citation{knuth1997}
+\citation{doe2021, roe2022}
+\@writefile{toc}{\contentsline {section}{Intro}{1}}
+\citation{knuth1997}\citation{*}
+\bibdata{refs}`
+
+	// Case 1: Comma-separated keys on several lines, each key once
+	expected := []string{"knuth1997", "doe2021", "roe2022", "*"}
+	keys, err := ParseAuxCitations(strings.NewReader(aux))
+	if err != nil || !reflect.DeepEqual(expected, keys) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", expected, keys, err)
+	}
+
+	// Case 2: No citations
+	if keys2, err2 := ParseAuxCitations(strings.NewReader(`\relax`)); err2 != nil || keys2 != nil {
+		t.Errorf("Expected no keys, but got '%#v' (%v)", keys2, err2)
+	}
+}
@@ -0,0 +1,56 @@
+// The clone.go source file includes functions to deep-copy BibTeX entries and files
+//
+// Clone: copies an Entry or a BibTeXFile without sharing maps and slices
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the entry. As Fields is a map, copying an Entry struct
+// shares the fields with the original; changes to the fields of the clone do not
+// affect the original entry.
+func (e *Entry) Clone() *Entry {
+	clone := *e
+	clone.Fields = maps.Clone(e.Fields)
+	clone.RawFieldNames = maps.Clone(e.RawFieldNames)
+	clone.FieldPositions = maps.Clone(e.FieldPositions)
+	clone.DuplicateFields = slices.Clone(e.DuplicateFields)
+	clone.ParseWarnings = slices.Clone(e.ParseWarnings)
+	clone.macroValues = maps.Clone(e.macroValues)
+	return &clone
+}
+
+// Clone returns a deep copy of the BibTeX file with clones of all entries (see Entry.Clone),
+// so the clone can be changed (e.g., by Merge or ResolveCrossrefs) without changing f.
+// The macros, comments, and errors are copied as well; the errors themselves are shared.
+func (f *BibTeXFile) Clone() *BibTeXFile {
+	return &BibTeXFile{
+		FilePath:        f.FilePath,
+		Entries:         cloneEntries(f.Entries),
+		Macros:          maps.Clone(f.Macros),
+		Errors:          slices.Clone(f.Errors),
+		Comments:        slices.Clone(f.Comments),
+		Preamble:        f.Preamble,
+		DisabledEntries: cloneEntries(f.DisabledEntries),
+		StrayText:       slices.Clone(f.StrayText),
+		TeXComments:     slices.Clone(f.TeXComments),
+		commentIndices:  slices.Clone(f.commentIndices),
+	}
+}
+
+// cloneEntries returns a slice with clones of the entries.
+func cloneEntries(entries []*Entry) []*Entry {
+	if entries == nil {
+		return nil
+	}
+	clones := make([]*Entry, len(entries))
+	for i, entry := range entries {
+		clones[i] = entry.Clone()
+	}
+	return clones
+}
@@ -0,0 +1,39 @@
+// Unit-tests for clone.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	file, _ := ParseString(`@string{pub = {ACM}}
+@comment{@misc{parked, title = {P}}}
+@book{a, title = {A}, year = {2020}, year = {2021}, publisher = pub}
+@inbook{b, title = {B}, crossref = {a}}`)
+
+	// Case 1: The clone equals the original
+	clone := file.Clone()
+	if !reflect.DeepEqual(file.Entries, clone.Entries) || !reflect.DeepEqual(file.Macros, clone.Macros) || !reflect.DeepEqual(file.DisabledEntries, clone.DisabledEntries) {
+		t.Errorf("Expected '%#v', but got '%#v'", file, clone)
+	}
+
+	// Case 2: Changing the clone does not change the original
+	clone.ResolveCrossrefs()
+	clone.Entries[0].Fields["title"] = "Changed"
+	clone.Entries[0].DuplicateFields[0] = "changed"
+	clone.Macros["pub"] = "IEEE"
+	clone.DisabledEntries[0].Fields["title"] = "Changed"
+	original, _ := file.LookupByKey("b")
+	if _, ok := original.Fields["year"]; ok {
+		t.Errorf("Expected no inherited fields, but got '%#v'", original.Fields)
+	}
+	if file.Entries[0].Fields["title"] != "A" || file.Entries[0].DuplicateFields[0] != "year" || file.Macros["pub"] != "ACM" || file.DisabledEntries[0].Fields["title"] != "P" {
+		t.Errorf("Expected the original to be unchanged, but got '%#v'", file)
+	}
+
+	// Case 3: The lookup index of the clone finds the cloned entries
+	if entry, ok := clone.LookupByKey("a"); !ok || entry != clone.Entries[0] {
+		t.Errorf("Expected '%#v', but got '%#v'", clone.Entries[0], entry)
+	}
+}
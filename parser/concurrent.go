@@ -0,0 +1,67 @@
+// The concurrent.go source file includes functions to parse raw BibTeX entries in parallel
+//
+// ParseConcurrent: parses raw entries (e.g., from SplitEntries) with a pool of workers
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Define errors
+type ErrChunk struct {
+	Index int   // The index of the raw entry in the chunks passed to ParseConcurrent.
+	Err   error // The error of the raw entry.
+}
+
+func (e *ErrChunk) Error() string {
+	return fmt.Sprintf("%s (chunk %d)", e.Err, e.Index)
+}
+
+func (e *ErrChunk) Unwrap() error {
+	return e.Err
+}
+
+// ParseConcurrent parses the raw entries (e.g., returned by SplitEntries) like ParseNewEntry,
+// using the given number of workers (all CPUs if workers < 1). The returned entries have the
+// order of the chunks, so entries[i] is parsed from chunks[i] (nil if the chunk cannot be
+// parsed at all). Every problem is returned as an ErrChunk with the index of its chunk,
+// ordered by the index.
+// As the chunks are parsed independently, @string macros are not resolved (except for
+// the month names), and @string, @comment, and @preamble blocks are parsed like entries.
+// Use ParseNewBibTeXFile if the macros are needed.
+func ParseConcurrent(chunks []string, workers int) ([]*Entry, []error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	entries := make([]*Entry, len(chunks))
+	chunkErrs := make([][]error, len(chunks))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(chunks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every index is written by exactly one worker, so no locking is needed
+			for i := range indices {
+				entries[i], chunkErrs[i] = parseEntry(chunks[i], nil, 0, ParseOptions{})
+			}
+		}()
+	}
+	for i := range chunks {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	var errs []error
+	for i, chunkErr := range chunkErrs {
+		for _, err := range chunkErr {
+			errs = append(errs, &ErrChunk{Index: i, Err: err})
+		}
+	}
+	return entries, errs
+}
@@ -0,0 +1,80 @@
+// Unit-tests for concurrent.go
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConcurrent(t *testing.T) {
+	chunks := []string{
+		`@misc{a, title = {A}}`,
+		`@misc{b, title = {B}`,
+		`@misc{c, title = {C}, month = mar}`,
+		`@misc{d e, title = {D}}`,
+	}
+
+	// Case 1: The entries keep the order of the chunks
+	entries, errs := ParseConcurrent(chunks, 3)
+	if len(entries) != len(chunks) {
+		t.Fatalf("Expected %d entries, but got %d", len(chunks), len(entries))
+	}
+	if entries[0].Key != "a" || entries[1] != nil || entries[2].Fields["month"] != "March" {
+		t.Errorf("Expected the entries in the order of the chunks, but got '%#v'", entries)
+	}
+
+	// Case 2: The errors are associated with their chunks
+	var indices []int
+	for _, err := range errs {
+		var chunkErr *ErrChunk
+		if errors.As(err, &chunkErr) {
+			indices = append(indices, chunkErr.Index)
+		}
+	}
+	expectedIndices := []int{1, 3}
+	if !reflect.DeepEqual(expectedIndices, indices) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedIndices, indices)
+	}
+	var invalidKey *ErrInvalidKey
+	if len(errs) != 2 || !errors.As(errs[1], &invalidKey) {
+		t.Errorf("Expected an ErrInvalidKey, but got '%#v'", errs)
+	}
+
+	// Case 3: Same result as a single worker
+	sequential, _ := ParseConcurrent(chunks, 1)
+	if !reflect.DeepEqual(sequential, entries) {
+		t.Errorf("Expected '%#v', but got '%#v'", sequential, entries)
+	}
+}
+
+// benchmarkChunks returns the raw texts of n generated entries.
+func benchmarkChunks(b *testing.B, n int) []string {
+	var builder strings.Builder
+	for i := range n {
+		fmt.Fprintf(&builder, "@article{key%d,\n  author = {Doe, Jane and Roe, Richard},\n  title = {{The Title} of Article %d},\n  journal = {Journal},\n  year = {2020},\n  pages = {1--10}\n}\n", i, i)
+	}
+	chunks, err := SplitEntries(strings.NewReader(builder.String()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return chunks
+}
+
+func BenchmarkParseSequential(b *testing.B) {
+	chunks := benchmarkChunks(b, 10000)
+	b.ResetTimer()
+	for range b.N {
+		ParseConcurrent(chunks, 1)
+	}
+}
+
+func BenchmarkParseConcurrent(b *testing.B) {
+	chunks := benchmarkChunks(b, 10000)
+	b.ResetTimer()
+	for range b.N {
+		ParseConcurrent(chunks, 0)
+	}
+}
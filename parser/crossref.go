@@ -0,0 +1,75 @@
+// The crossref.go source file includes functions to resolve the crossref field of BibTeX entries
+//
+// ResolveCrossrefs: copies the missing fields of entries from the entries they reference
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import "fmt"
+
+// Define errors
+type ErrMissingCrossref struct {
+	Key      string
+	Crossref string
+}
+
+func (e *ErrMissingCrossref) Error() string {
+	return fmt.Sprintf("Error resolving BibTeX entry '%s': crossref '%s' does not exist", e.Key, e.Crossref)
+}
+
+// nonInheritableFields are the fields that are never copied from the parent entry,
+// as they only describe the parent itself (following the biblatex defaults).
+var nonInheritableFields = map[string]bool{
+	"crossref": true, "entryset": true, "execute": true, "ids": true, "label": true,
+	"options": true, "presort": true, "related": true, "relatedoptions": true,
+	"relatedstring": true, "relatedtype": true, "shorthand": true, "shorthandintro": true,
+	"sortkey": true, "subtitle": true, "title": true, "titleaddon": true, "xdata": true, "xref": true,
+}
+
+// ResolveCrossrefs copies the missing fields of every entry with a crossref field from the
+// referenced parent entry, e.g., the publisher and year of a @book to its @inbook chapters.
+// The title of the parent becomes the booktitle of the child, if neither of them
+// has a booktitle. Fields describing only the parent (e.g., title, ids, shorthand) are not
+// copied, and fields of the child are never overwritten. Chains of crossrefs are resolved
+// from the top, so a child inherits the fields its parent inherited.
+// ResolveCrossrefs returns one ErrMissingCrossref per crossref that does not match a key.
+// As inherited fields can satisfy the required fields of an entry, it should be
+// called before Validate.
+func (f *BibTeXFile) ResolveCrossrefs() []error {
+	var errs []error
+	resolved := make(map[*Entry]bool, len(f.Entries))
+	var resolve func(entry *Entry)
+	resolve = func(entry *Entry) {
+		if resolved[entry] {
+			return
+		}
+		// Mark the entry first to stop at cyclic crossrefs
+		resolved[entry] = true
+		crossref, ok := entry.Fields["crossref"]
+		if !ok {
+			return
+		}
+		parent, ok := f.LookupByKey(crossref)
+		if !ok {
+			errs = append(errs, &ErrMissingCrossref{Key: entry.Key, Crossref: crossref})
+			return
+		}
+		resolve(parent)
+		entry.inheritFields(parent)
+	}
+	for _, entry := range f.Entries {
+		resolve(entry)
+	}
+	return errs
+}
+
+// inheritFields copies the inheritable fields of the parent that are missing in the entry.
+func (e *Entry) inheritFields(parent *Entry) {
+	e.copyMissingFields(parent, nonInheritableFields)
+	if title, ok := parent.Fields["title"]; ok {
+		if _, ok := e.Fields["booktitle"]; !ok {
+			e.Fields["booktitle"] = title
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Unit-tests for crossref.go
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveCrossrefs(t *testing.T) {
+	bib := `@inbook{chapter1, author = {Jane Doe}, title = {Chapter One}, chapter = {1}, crossref = {book1}}
+@book{book1, editor = {John Smith}, title = {The Book}, Publisher = {ACM}, year = {2020}, shorthand = {TB}, crossref = {series1}}
+@book{series1, address = {New York}, year = {1999}}
+@inbook{chapter2, author = {Max Mustermann}, title = {Chapter Two}, pages = {1--10}, crossref = {missing}}`
+	file, _ := ParseNewBibTeXFile(strings.NewReader(bib))
+
+	// Case 1: Missing crossref targets are reported
+	expectedErrs := []error{&ErrMissingCrossref{Key: "chapter2", Crossref: "missing"}}
+	if errs := file.ResolveCrossrefs(); !reflect.DeepEqual(expectedErrs, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedErrs, errs)
+	}
+
+	// Case 2: Missing fields are inherited through the chain, but not overwritten
+	expectedFields := map[string]string{
+		"author":    "Jane Doe",
+		"title":     "Chapter One",
+		"chapter":   "1",
+		"crossref":  "book1",
+		"booktitle": "The Book",
+		"editor":    "John Smith",
+		"publisher": "ACM",
+		"year":      "2020",
+		"address":   "New York",
+	}
+	chapter1, _ := file.LookupByKey("chapter1")
+	if !reflect.DeepEqual(expectedFields, chapter1.Fields) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedFields, chapter1.Fields)
+	}
+	if rawFieldName := chapter1.RawFieldName("publisher"); rawFieldName != "Publisher" {
+		t.Errorf("Expected '%#v', but got '%#v'", "Publisher", rawFieldName)
+	}
+
+	// Case 3: Inherited fields satisfy the required fields
+	if errs := chapter1.Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+
+	// Case 4: Cyclic crossrefs terminate
+	cyclic := `@misc{a, title = {A}, crossref = {b}}
+@misc{b, note = {B}, crossref = {a}}`
+	cyclicFile, _ := ParseNewBibTeXFile(strings.NewReader(cyclic))
+	if errs := cyclicFile.ResolveCrossrefs(); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+	if entry, _ := cyclicFile.LookupByKey("a"); entry.Fields["note"] != "B" {
+		t.Errorf("Expected '%#v', but got '%#v'", "B", entry.Fields["note"])
+	}
+}
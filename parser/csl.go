@@ -0,0 +1,234 @@
+// The csl.go source file includes functions to export BibTeX entries as CSL-JSON
+//
+// ToCSL: maps an Entry to a CSL item
+// MarshalCSLJSON: exports all entries of a BibTeXFile as a CSL-JSON array
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Define errors
+type ErrUnmappedField struct {
+	Key    string
+	Field  string
+	Format string
+}
+
+func (e *ErrUnmappedField) Error() string {
+	return fmt.Sprintf("Warning exporting BibTeX entry '%s': field '%s' cannot be mapped to %s and is dropped", e.Key, e.Field, e.Format)
+}
+
+// cslTypes maps BibTeX entry types to CSL item types.
+// Unknown entry types are exported as "document".
+var cslTypes = map[EntryType]string{
+	EntryArticle:       "article-journal",
+	EntryBook:          "book",
+	EntryBooklet:       "pamphlet",
+	EntryConference:    "paper-conference",
+	EntryInBook:        "chapter",
+	EntryInCollection:  "chapter",
+	EntryInProceedings: "paper-conference",
+	EntryManual:        "report",
+	EntryMastersThesis: "thesis",
+	EntryMisc:          "document",
+	EntryOnline:        "webpage",
+	EntryPhDThesis:     "thesis",
+	EntryProceedings:   "book",
+	EntryTechReport:    "report",
+	EntryUnpublished:   "manuscript",
+}
+
+// cslGenres maps BibTeX entry types to the CSL genre variable.
+var cslGenres = map[EntryType]string{
+	EntryMastersThesis: "Master's thesis",
+	EntryPhDThesis:     "PhD thesis",
+}
+
+// cslVariables maps BibTeX fields to CSL variables.
+// The fields author, editor, year, month, date, and pages are handled separately.
+var cslVariables = map[string]string{
+	"abstract":     "abstract",
+	"address":      "publisher-place",
+	"booktitle":    "container-title",
+	"chapter":      "chapter-number",
+	"doi":          "DOI",
+	"edition":      "edition",
+	"institution":  "publisher",
+	"isbn":         "ISBN",
+	"issn":         "ISSN",
+	"journal":      "container-title",
+	"keywords":     "keyword",
+	"language":     "language",
+	"note":         "note",
+	"number":       "number",
+	"organization": "publisher",
+	"publisher":    "publisher",
+	"school":       "publisher",
+	"series":       "collection-title",
+	"title":        "title",
+	"url":          "URL",
+	"volume":       "volume",
+}
+
+// Regex to match an ISO date like 2024, 2024-03, or 2024-03-12 (biblatex date field)
+var regexISODate = regexp.MustCompile(`^(\d{4})(?:-(\d{1,2}))?(?:-(\d{1,2}))?$`)
+
+// ToCSL maps the entry to a CSL item, which can be marshaled to CSL-JSON.
+// The entry type is mapped to the CSL type (e.g., article to article-journal), the
+// author and editor fields are split into CSL name objects (see ParseName), and
+// year/month (or the biblatex date) become the issued date-parts. Braces protecting
+// the capitalization are removed from all values.
+// Fields without a CSL variable are dropped. The item is returned in any case, but the
+// error joins an ErrUnmappedField for every dropped field.
+func (e *Entry) ToCSL() (map[string]any, error) {
+	entryType, _ := e.TypedType()
+	item := map[string]any{"id": e.Key}
+	if cslType, ok := cslTypes[entryType]; ok {
+		item["type"] = cslType
+	} else {
+		item["type"] = "document"
+	}
+	if genre, ok := cslGenres[entryType]; ok {
+		item["genre"] = genre
+	}
+	var warnings []error
+	for _, fieldName := range e.fieldOrder() {
+		value := stripBraces(e.Fields[fieldName])
+		switch fieldName {
+		case "author", "editor":
+			item[fieldName] = cslNames(e.Fields[fieldName])
+		case "year", "month", "date":
+			// Handled below
+		case "pages":
+			item["page"] = strings.ReplaceAll(value, "--", "-")
+		case "number":
+			// The number of an article is the issue of the journal
+			if entryType == EntryArticle {
+				item["issue"] = value
+			} else {
+				item["number"] = value
+			}
+		default:
+			variable, ok := cslVariables[fieldName]
+			if !ok {
+				warnings = append(warnings, &ErrUnmappedField{Key: e.Key, Field: fieldName, Format: "CSL-JSON"})
+				continue
+			}
+			// Keep the first value if several fields map to the same variable
+			if _, exists := item[variable]; !exists {
+				item[variable] = value
+			}
+		}
+	}
+	if issued, ok := e.cslIssued(); ok {
+		item["issued"] = issued
+	}
+	return item, errors.Join(warnings...)
+}
+
+// MarshalCSLJSON exports all entries as a CSL-JSON array, see ToCSL. @xdata entries are
+// skipped, as they only provide fields for other entries (see ResolveXData).
+// Dropped fields do not fail the export: the JSON is returned together with an error
+// joining an ErrUnmappedField for every dropped field.
+func (f *BibTeXFile) MarshalCSLJSON() ([]byte, error) {
+	items := make([]map[string]any, 0, len(f.Entries))
+	var warnings []error
+	for _, entry := range f.Entries {
+		if !entry.isPrinted() {
+			continue
+		}
+		item, err := entry.ToCSL()
+		if err != nil {
+			warnings = append(warnings, err)
+		}
+		items = append(items, item)
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	return data, errors.Join(warnings...)
+}
+
+// cslIssued returns the CSL date of the entry, based on the year and month fields
+// or the biblatex date field.
+func (e *Entry) cslIssued() (map[string]any, bool) {
+	var dateParts []int
+	if year, ok := e.Fields["year"]; ok {
+		year = stripBraces(strings.TrimSpace(year))
+		yearNumber, err := strconv.Atoi(year)
+		if err != nil {
+			// Keep non-numeric years like "forthcoming"
+			return map[string]any{"literal": year}, year != ""
+		}
+		dateParts = append(dateParts, yearNumber)
+		if month, ok := e.monthNumber(); ok {
+			dateParts = append(dateParts, month)
+		}
+	} else if date, ok := e.Fields["date"]; ok {
+		match := regexISODate.FindStringSubmatch(strings.TrimSpace(date))
+		if match == nil {
+			return map[string]any{"literal": date}, date != ""
+		}
+		for _, part := range match[1:] {
+			if number, err := strconv.Atoi(part); err == nil {
+				dateParts = append(dateParts, number)
+			}
+		}
+	} else {
+		return nil, false
+	}
+	return map[string]any{"date-parts": [][]int{dateParts}}, true
+}
+
+// cslNames splits a BibTeX name list into CSL name objects.
+// Names completely enclosed in braces (e.g., {Barnes and Noble}) are corporate names
+// and exported as literal.
+func cslNames(nameList string) []map[string]string {
+	var names []map[string]string
+	for _, name := range SplitNames(nameList) {
+		if isCorporateName(name) {
+			names = append(names, map[string]string{"literal": stripBraces(name)})
+			continue
+		}
+		first, von, last, jr := ParseName(name)
+		cslName := map[string]string{"family": stripBraces(last)}
+		if first != "" {
+			cslName["given"] = stripBraces(first)
+		}
+		if von != "" {
+			cslName["non-dropping-particle"] = stripBraces(von)
+		}
+		if jr != "" {
+			cslName["suffix"] = stripBraces(jr)
+		}
+		names = append(names, cslName)
+	}
+	return names
+}
+
+// isCorporateName checks if the name is completely enclosed in braces (e.g., {Barnes and Noble}).
+func isCorporateName(name string) bool {
+	return len(name) >= 2 && name[0] == '{' && findClosingBrace(name[1:]) == len(name)-2
+}
+
+// stripBraces removes all braces from a value which are not escaped (e.g., {NASA} becomes NASA).
+func stripBraces(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		if (value[i] == '{' || value[i] == '}') && (i == 0 || value[i-1] != '\\') {
+			continue
+		}
+		builder.WriteByte(value[i])
+	}
+	return builder.String()
+}
@@ -0,0 +1,96 @@
+// Unit-tests for csl.go
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToCSL(t *testing.T) {
+	// Case 1: Article with authors, year, and month
+	entry1, _ := ParseNewEntry(`@article{muster2024,
+  author  = {Mustermann, Max and Erika von Musterfrau},
+  title   = {Einführung in die {D}atenwissenschaft},
+  journal = {Journal für Informatik},
+  number  = {3},
+  pages   = {10--20},
+  year    = {2024},
+  month   = mar
+}`)
+	expected1 := map[string]any{
+		"id":   "muster2024",
+		"type": "article-journal",
+		"author": []map[string]string{
+			{"family": "Mustermann", "given": "Max"},
+			{"family": "Musterfrau", "given": "Erika", "non-dropping-particle": "von"},
+		},
+		"title":           "Einführung in die Datenwissenschaft",
+		"container-title": "Journal für Informatik",
+		"issue":           "3",
+		"page":            "10-20",
+		"issued":          map[string]any{"date-parts": [][]int{{2024, 3}}},
+	}
+	item1, err1 := entry1.ToCSL()
+	if err1 != nil {
+		t.Errorf("Expected no error, but got '%#v'", err1)
+	}
+	if !reflect.DeepEqual(expected1, item1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, item1)
+	}
+
+	// Case 2: Unmappable fields are dropped with a warning
+	entry2, _ := ParseNewEntry(`@book{weber2020, editor = {{Barnes and Noble}}, title = {Sammelband}, publisher = {Technik Verlag}, year = {2020}, owner = {thomas}}`)
+	expected2 := map[string]any{
+		"id":        "weber2020",
+		"type":      "book",
+		"editor":    []map[string]string{{"literal": "Barnes and Noble"}},
+		"title":     "Sammelband",
+		"publisher": "Technik Verlag",
+		"issued":    map[string]any{"date-parts": [][]int{{2020}}},
+	}
+	item2, err2 := entry2.ToCSL()
+	if !reflect.DeepEqual(expected2, item2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, item2)
+	}
+	var unmapped *ErrUnmappedField
+	if !errors.As(err2, &unmapped) || unmapped.Field != "owner" || unmapped.Key != "weber2020" {
+		t.Errorf("Expected '%#v', but got '%#v'", &ErrUnmappedField{Key: "weber2020", Field: "owner", Format: "CSL-JSON"}, err2)
+	}
+
+	// Case 3: Thesis with a biblatex date
+	entry3, _ := ParseNewEntry(`@phdthesis{muster2023, author = {Max Mustermann}, title = {Diss}, school = {Uni}, date = {2023-12-20}}`)
+	item3, _ := entry3.ToCSL()
+	if item3["type"] != "thesis" || item3["genre"] != "PhD thesis" {
+		t.Errorf("Expected '%#v', but got '%#v'", "thesis", item3["type"])
+	}
+	expected3 := map[string]any{"date-parts": [][]int{{2023, 12, 20}}}
+	if !reflect.DeepEqual(expected3, item3["issued"]) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, item3["issued"])
+	}
+}
+
+func TestMarshalCSLJSON(t *testing.T) {
+	// Case 1: Export of a file with a dropped field
+	bibtexFile, _ := ParseNewBibTeXFile(strings.NewReader(`@misc{a, title = {A}, year = {2020}}
+@misc{b, title = {B}, owner = {thomas}}`))
+	expected1 := `[{"id":"a","issued":{"date-parts":[[2020]]},"title":"A","type":"document"},{"id":"b","title":"B","type":"document"}]`
+	data1, err1 := bibtexFile.MarshalCSLJSON()
+	if string(data1) != expected1 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, string(data1))
+	}
+	var unmapped *ErrUnmappedField
+	if !errors.As(err1, &unmapped) {
+		t.Errorf("Expected an ErrUnmappedField, but got '%#v'", err1)
+	}
+}
+
+func TestStripBraces(t *testing.T) {
+	// Case 1: Protected capitalization and escaped braces
+	expected1 := `The NASA \{x\}`
+	result1 := stripBraces(`The {NASA} \{x\}`)
+	if expected1 != result1 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, result1)
+	}
+}
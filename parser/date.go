@@ -0,0 +1,33 @@
+// The date.go source file includes functions to get the publication date of entries
+//
+// Year: returns the year of an Entry based on the year or date field
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// Regex to find a 4-digit year, e.g., in 2024, 2023-12-20, or 20.12.2023
+var regexYear = regexp.MustCompile(`(?:^|[^0-9])([0-9]{4})(?:[^0-9]|$)`)
+
+// Year returns the publication year of the entry.
+// The year field is preferred; if it does not contain a 4-digit year, the year is
+// extracted from the biblatex date field (e.g., 2023-12-20 or 20.12.2023).
+// ok is false if neither field contains a usable year.
+func (e *Entry) Year() (year int, ok bool) {
+	for _, fieldName := range []string{"year", "date"} {
+		match := regexYear.FindStringSubmatch(e.Fields[fieldName])
+		if match == nil {
+			continue
+		}
+		year, err := strconv.Atoi(match[1])
+		if err == nil {
+			return year, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,34 @@
+// Unit-tests for date.go
+package parser
+
+import "testing"
+
+func TestYear(t *testing.T) {
+	cases := []struct {
+		fields   map[string]string
+		expected int
+		ok       bool
+	}{
+		// Case 1: year field
+		{map[string]string{"year": "2024"}, 2024, true},
+		// Case 2: year field is preferred over date
+		{map[string]string{"year": "2024", "date": "2023-12-20"}, 2024, true},
+		// Case 3: ISO date
+		{map[string]string{"date": "2023-12-20"}, 2023, true},
+		// Case 4: German date
+		{map[string]string{"date": "20.12.2023"}, 2023, true},
+		// Case 5: Unusable year falls back to the date
+		{map[string]string{"year": "forthcoming", "date": "2025"}, 2025, true},
+		// Case 6: No usable year
+		{map[string]string{"year": "n.d.", "date": "12345"}, 0, false},
+		// Case 7: No fields
+		{map[string]string{}, 0, false},
+	}
+	for i, c := range cases {
+		entry := &Entry{Fields: c.fields}
+		year, ok := entry.Year()
+		if year != c.expected || ok != c.ok {
+			t.Errorf("Case %d: Expected '%#v', but got '%#v'", i+1, c.expected, year)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// The dedup.go source file includes functions to remove duplicate entries of a BibTeXFile
+//
+// Deduplicate: removes entries with the same entry type and fields as an earlier entry
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// Deduplicate removes the entries that are exact duplicates of an earlier entry, e.g., the
+// same entry added under different keys by Merge or ParseFiles, and returns the number of
+// removed entries. Two entries are duplicates if they have the same entry type (ignoring the
+// case) and the same fields, ignoring the order of the fields and white spaces in the values
+// (e.g., "A  Title" and "A Title" are equal); their keys are not compared. The first entry
+// is kept, and the crossref, xref, entryset, xdata, and related fields referencing a removed
+// key are changed to the key of the kept entry (see RenameKey). The citations of the removed
+// keys in LaTeX documents are not changed. Entries that only have similar titles are kept,
+// see FindSimilar.
+func (f *BibTeXFile) Deduplicate() (removed int) {
+	survivors := make(map[string]*Entry, len(f.Entries))
+	entries := make([]*Entry, 0, len(f.Entries))
+	for _, entry := range f.Entries {
+		fingerprint := entry.fingerprint()
+		survivor, ok := survivors[fingerprint]
+		if !ok {
+			survivors[fingerprint] = entry
+			entries = append(entries, entry)
+			continue
+		}
+		removed++
+		if entry.Key != survivor.Key {
+			f.replaceKeyReferences(entry.Key, survivor.Key)
+		}
+	}
+	if removed > 0 {
+		f.SetEntries(entries)
+	}
+	return removed
+}
+
+// fingerprint returns the lowercased entry type and the sorted fields of the entry with
+// normalized white spaces, so entries with the same fingerprint are duplicates (see
+// Deduplicate).
+func (e *Entry) fingerprint() string {
+	fields := make([]string, 0, len(e.Fields))
+	for fieldName, value := range e.Fields {
+		fields = append(fields, fieldName+"="+strings.Join(strings.Fields(value), " "))
+	}
+	sort.Strings(fields)
+	// The fields are separated by null chars, which do not appear in BibTeX files
+	return strings.ToLower(e.EntryType) + "\x00" + strings.Join(fields, "\x00")
+}
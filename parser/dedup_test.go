@@ -0,0 +1,41 @@
+// Unit-tests for dedup.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeduplicate(t *testing.T) {
+	bibtexFile, _ := ParseString(`@book{a, title = {A  Title}, publisher = {P}, year = {2020}}
+@Book{b, year = {2020}, publisher = {P},
+  title = {A Title}}
+@book{c, title = {A Title}, publisher = {Q}, year = {2020}}
+@inbook{ch1, crossref = {b}, pages = {1--2}}
+@set{set, entryset = {a, b, c}}
+@book{a, title = {A Title}, publisher = {P}, year = {2020}}`)
+
+	// Case 1: Exact duplicates are removed, ignoring the case of the type, the field order, and white spaces
+	if removed := bibtexFile.Deduplicate(); removed != 2 {
+		t.Errorf("Expected '%#v', but got '%#v'", 2, removed)
+	}
+	expectedKeys := []string{"a", "c", "ch1", "set"}
+	if keys := entryKeys(bibtexFile.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+	if entry, ok := bibtexFile.LookupByKey("b"); ok {
+		t.Errorf("Expected '%#v' to be removed, but got '%#v'", "b", entry)
+	}
+
+	// Case 2: References to removed keys point to the kept entry
+	expected := map[string]string{"crossref": "a", "entryset": "a, c"}
+	got := map[string]string{"crossref": bibtexFile.Entries[2].Fields["crossref"], "entryset": bibtexFile.Entries[3].Fields["entryset"]}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, got)
+	}
+
+	// Case 3: Nothing to remove
+	if removed := bibtexFile.Deduplicate(); removed != 0 {
+		t.Errorf("Expected '%#v', but got '%#v'", 0, removed)
+	}
+}
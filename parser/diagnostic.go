@@ -0,0 +1,185 @@
+// The diagnostic.go source file includes a common type for all problems found by the parser
+//
+// Diagnostic: a problem with its severity, message, entry key, and position
+// NewDiagnostic: converts an error into a Diagnostic
+// Diagnostics: collects the parsing errors and validation problems of a BibTeXFile
+// HasErrors: reports whether any Diagnostic is an error
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Severity tells how serious a Diagnostic is.
+type Severity int
+
+const (
+	SeverityError   Severity = iota // The entry is broken or incomplete.
+	SeverityWarning                 // A possible problem, the entry is still usable (see IsWarning).
+	SeverityInfo                    // No problem, e.g., an entry disabled in a @comment block.
+)
+
+// String returns the lowercased name of the severity, e.g., "warning".
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Position is the location of a Diagnostic in the BibTeX file.
+type Position struct {
+	Line   int // The line in the BibTeX file (0 if the position is unknown).
+	Offset int // The byte offset of the entry in the parsed input, see Entry.StartOffset.
+}
+
+// Diagnostic is a problem found while parsing or validating a BibTeX file, e.g., to print
+// errors and warnings differently or to show them at their position in an editor.
+type Diagnostic struct {
+	Severity Severity
+	Message  string
+	Key      string   // The key of the entry ("" if the problem is not about a single entry).
+	Position Position // The position of the problem or of its entry (Line is 0 if unknown).
+	Err      error    // The error of the problem (nil for infos).
+}
+
+// String returns the message of the diagnostic.
+func (d Diagnostic) String() string {
+	return d.Message
+}
+
+// entryKeyer is implemented by all errors about a single entry, see NewDiagnostic.
+type entryKeyer interface {
+	entryKey() string
+}
+
+func (e *ErrInvalidKey) entryKey() string           { return e.Key }
+func (e *ErrMissingComma) entryKey() string         { return e.Key }
+func (e *ErrExtraComma) entryKey() string           { return e.Key }
+func (e *ErrMissingField) entryKey() string         { return e.Key }
+func (e *ErrUnknownEntryType) entryKey() string     { return e.Key }
+func (e *ErrEmptyRequiredField) entryKey() string   { return e.Key }
+func (e *ErrExclusiveFields) entryKey() string      { return e.Key }
+func (e *ErrUnknownField) entryKey() string         { return e.Key }
+func (e *ErrDuplicateField) entryKey() string       { return e.Key }
+func (e *ErrInvalidDOI) entryKey() string           { return e.Key }
+func (e *ErrUnresolvedDOI) entryKey() string        { return e.Key }
+func (e *ErrInvalidISBN) entryKey() string          { return e.Key }
+func (e *ErrPagesHyphen) entryKey() string          { return e.Key }
+func (e *ErrReversedPages) entryKey() string        { return e.Key }
+func (e *ErrInvalidURL) entryKey() string           { return e.Key }
+func (e *ErrSuspiciousValue) entryKey() string      { return e.Key }
+func (e *ErrTypographicChar) entryKey() string      { return e.Key }
+func (e *ErrUnknownMonth) entryKey() string         { return e.Key }
+func (e *ErrMissingCrossref) entryKey() string      { return e.Key }
+func (e *ErrMissingSetMember) entryKey() string     { return e.Key }
+func (e *ErrMissingXData) entryKey() string         { return e.Key }
+func (e *ErrUnmappedField) entryKey() string        { return e.Key }
+func (e *ErrUnusedEntry) entryKey() string          { return e.Key }
+func (e *ErrInconsistentNameForm) entryKey() string { return e.Key }
+func (e *ErrDeadLink) entryKey() string             { return e.Key }
+func (e *ErrDiscouragedField) entryKey() string     { return e.Key }
+func (e *ErrInvalidArxivID) entryKey() string       { return e.Key }
+func (e *ErrMissingPrimaryClass) entryKey() string  { return e.Key }
+func (e *ErrKeyCollision) entryKey() string         { return e.NewKey }
+func (e *ErrMissingClosingBrace) entryKey() string  { return e.Key }
+func (e *ErrLossyWrite) entryKey() string           { return e.Key }
+
+// NewDiagnostic converts err into a Diagnostic. Warnings (see IsWarning) get the
+// SeverityWarning, all other errors the SeverityError. The key is set for errors about a
+// single entry (e.g., ErrMissingField) and the line for parsing errors and ErrMissingKey
+// errors with a known line.
+func NewDiagnostic(err error) Diagnostic {
+	diagnostic := Diagnostic{Severity: SeverityError, Message: err.Error(), Err: err}
+	if IsWarning(err) {
+		diagnostic.Severity = SeverityWarning
+	}
+	var keyer entryKeyer
+	if errors.As(err, &keyer) {
+		diagnostic.Key = keyer.entryKey()
+	}
+	var parsingErr *ErrParsingEntry
+	var unbalancedErr *ErrUnbalancedBraces
+	var mismatchedErr *ErrMismatchedDelimiters
+	var strayErr *ErrStrayText
+	var braceErr *ErrMissingClosingBrace
+	var keyErr *ErrMissingKey
+	var writeErr *ErrLossyWrite
+	switch {
+	case errors.As(err, &parsingErr):
+		diagnostic.Position.Line = parsingErr.Line
+	case errors.As(err, &unbalancedErr):
+		diagnostic.Position.Line = unbalancedErr.Line
+	case errors.As(err, &mismatchedErr):
+		diagnostic.Position.Line = mismatchedErr.Line
+	case errors.As(err, &strayErr):
+		diagnostic.Position.Line = strayErr.Line
+	case errors.As(err, &braceErr):
+		diagnostic.Position.Line = braceErr.Line
+	case errors.As(err, &keyErr):
+		diagnostic.Position = Position{Line: keyErr.Line, Offset: keyErr.Offset}
+	case errors.As(err, &writeErr):
+		diagnostic.Position.Line = writeErr.Line
+	}
+	return diagnostic
+}
+
+// DiagnosticsOf converts the errors into Diagnostics (see NewDiagnostic). Diagnostics with
+// the key of an entry in the file, but without a line, get the position of the entry.
+func (f *BibTeXFile) DiagnosticsOf(errs []error) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(errs))
+	for _, err := range errs {
+		diagnostic := NewDiagnostic(err)
+		if entry, ok := f.LookupByKey(diagnostic.Key); ok && diagnostic.Key != "" && diagnostic.Position.Line == 0 {
+			diagnostic.Position = Position{Line: entry.Line, Offset: entry.StartOffset}
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// Diagnostics returns the parsing errors of the file, the warnings about text between the
+// entries (see CheckStrayText), the problems of all entries validated with opts (see
+// ValidateWithOptions), and one SeverityInfo diagnostic per entry that is disabled in a
+// @comment block, in this order. To check inherited fields, ResolveXData and
+// ResolveCrossrefs should be called before.
+func (f *BibTeXFile) Diagnostics(ctx context.Context, opts ValidateOptions) []Diagnostic {
+	diagnostics := f.DiagnosticsOf(append(append([]error{}, f.Errors...), f.CheckStrayText()...))
+	for _, entry := range f.Entries {
+		for _, err := range entry.ValidateWithOptions(ctx, opts) {
+			diagnostic := NewDiagnostic(err)
+			diagnostic.Key = entry.Key
+			diagnostic.Position = Position{Line: entry.Line, Offset: entry.StartOffset}
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+	for _, entry := range f.DisabledEntries {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("Info: entry '%s' is disabled in a @comment block", entry.Key),
+			Key:      entry.Key,
+		})
+	}
+	return diagnostics
+}
+
+// HasErrors reports whether any of the diagnostics has the SeverityError.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Unit-tests for diagnostic.go
+package parser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNewDiagnostic(t *testing.T) {
+	// Case 1: Severities, keys, and lines of errors and warnings
+	cases := []struct {
+		err      error
+		expected Diagnostic
+	}{
+		{&ErrMissingField{EntryType: "article", Field: "title", Key: "a"}, Diagnostic{Severity: SeverityError, Key: "a"}},
+		{&ErrUnknownField{Key: "b", Field: "titel"}, Diagnostic{Severity: SeverityWarning, Key: "b"}},
+		{&ErrParsingEntry{Message: "Broken.", Line: 7}, Diagnostic{Severity: SeverityError, Position: Position{Line: 7}}},
+		{&ErrInFile{Path: "x.bib", Err: &ErrUnbalancedBraces{Line: 3}}, Diagnostic{Severity: SeverityError, Position: Position{Line: 3}}},
+		{&ErrInFile{Path: "x.bib", Err: &ErrMissingComma{Key: "c", Field: "year"}}, Diagnostic{Severity: SeverityWarning, Key: "c"}},
+	}
+	for _, c := range cases {
+		c.expected.Message = c.err.Error()
+		c.expected.Err = c.err
+		if diagnostic := NewDiagnostic(c.err); !reflect.DeepEqual(c.expected, diagnostic) {
+			t.Errorf("Expected '%#v', but got '%#v'", c.expected, diagnostic)
+		}
+	}
+
+	// Case 2: Names of the severities
+	for severity, expected := range map[Severity]string{SeverityError: "error", SeverityWarning: "warning", SeverityInfo: "info", 7: "Severity(7)"} {
+		if severity.String() != expected {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, severity.String())
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	bibtexFile, _ := ParseString(`@misc{a, title = {A}}
+
+@article{b, title = {B}, titel = {C}}
+@comment{@misc{disabled, title = {D}}}
+@misc{broken, title = {unclosed}`)
+
+	// Case 1: Parsing errors, validation problems, and disabled entries with their positions
+	diagnostics := bibtexFile.Diagnostics(context.Background(), ValidateOptions{})
+	type summary struct {
+		Severity Severity
+		Key      string
+		Line     int
+	}
+	var got []summary
+	for _, diagnostic := range diagnostics {
+		got = append(got, summary{diagnostic.Severity, diagnostic.Key, diagnostic.Position.Line})
+	}
+	expected := []summary{
+		{SeverityError, "", 5},
+		{SeverityError, "b", 3},
+		{SeverityError, "b", 3},
+		{SeverityError, "b", 3},
+		{SeverityWarning, "b", 3},
+		{SeverityInfo, "disabled", 0},
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, got)
+	}
+	if diagnostics[1].Position.Offset != 23 {
+		t.Errorf("Expected '%#v', but got '%#v'", 23, diagnostics[1].Position.Offset)
+	}
+	if !HasErrors(diagnostics) || HasErrors(diagnostics[4:]) {
+		t.Errorf("Expected only the first diagnostics to be errors, but got '%#v'", diagnostics)
+	}
+
+	// Case 2: Errors of file-level checks get the position of their entry
+	diagnostics2 := bibtexFile.DiagnosticsOf([]error{&ErrUnusedEntry{Key: "b"}, &ErrMissingCitation{Key: "missing"}})
+	if diagnostics2[0].Position.Line != 3 || diagnostics2[0].Severity != SeverityWarning || diagnostics2[1].Position.Line != 0 {
+		t.Errorf("Expected the line of entry '%s', but got '%#v'", "b", diagnostics2)
+	}
+}
@@ -0,0 +1,42 @@
+// The entryset.go source file includes functions for biblatex entry sets (@set entries)
+//
+// EntrySet: returns the member keys of the entryset field of an Entry
+// CheckEntrySets: checks that all members of the entry sets of a BibTeXFile exist
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import "fmt"
+
+// Define errors
+type ErrMissingSetMember struct {
+	Key    string
+	Member string
+}
+
+func (e *ErrMissingSetMember) Error() string {
+	return fmt.Sprintf("Error resolving BibTeX entry '%s': entry set member '%s' does not exist", e.Key, e.Member)
+}
+
+// EntrySet returns the member keys of the entryset field (e.g., entryset = {a, b, c}),
+// which groups entries in a biblatex @set entry. The keys are trimmed and empty keys are
+// dropped. It returns nil if the entry has no entryset field.
+func (e *Entry) EntrySet() []string {
+	return splitKeyList(e.Fields["entryset"])
+}
+
+// CheckEntrySets checks the entryset fields of all entries (see EntrySet) and returns one
+// ErrMissingSetMember per member key that does not match the key of an entry in the file.
+// Like ResolveCrossrefs, it should be called before the file is used by biblatex.
+func (f *BibTeXFile) CheckEntrySets() []error {
+	var errs []error
+	for _, entry := range f.Entries {
+		for _, member := range entry.EntrySet() {
+			if _, ok := f.LookupByKey(member); !ok {
+				errs = append(errs, &ErrMissingSetMember{Key: entry.Key, Member: member})
+			}
+		}
+	}
+	return errs
+}
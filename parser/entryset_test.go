@@ -0,0 +1,40 @@
+// Unit-tests for entryset.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntrySet(t *testing.T) {
+	file, _ := ParseString(`@set{set1, entryset = { a,b , missing,, }}
+@article{a, author = {Jane Doe}, title = {A}, journal = {J}, year = {2020}}
+@article{b, author = {Jane Doe}, title = {B}, journal = {J}, year = {2021}}
+@misc{c, title = {No set}}`)
+
+	// Case 1: Member keys are trimmed and empty keys are dropped
+	set, _ := file.LookupByKey("set1")
+	expected := []string{"a", "b", "missing"}
+	if members := set.EntrySet(); !reflect.DeepEqual(expected, members) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, members)
+	}
+	if members := file.Entries[3].EntrySet(); members != nil {
+		t.Errorf("Expected no members, but got '%#v'", members)
+	}
+
+	// Case 2: Missing members are reported
+	expectedErrs := []error{&ErrMissingSetMember{Key: "set1", Member: "missing"}}
+	if errs := file.CheckEntrySets(); !reflect.DeepEqual(expectedErrs, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedErrs, errs)
+	}
+
+	// Case 3: @set is a known entry type requiring an entryset
+	if errs := set.Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+	empty, _ := ParseNewEntry(`@set{set2, note = {N}}`)
+	expectedErrs = []error{&ErrMissingField{EntryType: "set", Field: "entryset", Key: "set2"}}
+	if errs := empty.Validate(); !reflect.DeepEqual(expectedErrs, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedErrs, errs)
+	}
+}
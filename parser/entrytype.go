@@ -0,0 +1,93 @@
+// The entrytype.go source file includes the entry types of BibTeX and biblatex as constants
+//
+// ParseEntryTypeName: returns the EntryType of an entry type name like Article
+// TypedType: returns the EntryType of an Entry
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import "strings"
+
+// EntryType is a known BibTeX or biblatex entry type, e.g., EntryArticle.
+// Its value is the lowercased name of the type as used in Entry.EntryType.
+type EntryType string
+
+// The entry types of the standard BibTeX styles
+const (
+	EntryArticle       EntryType = "article"
+	EntryBook          EntryType = "book"
+	EntryBooklet       EntryType = "booklet"
+	EntryConference    EntryType = "conference"
+	EntryInBook        EntryType = "inbook"
+	EntryInCollection  EntryType = "incollection"
+	EntryInProceedings EntryType = "inproceedings"
+	EntryManual        EntryType = "manual"
+	EntryMastersThesis EntryType = "mastersthesis"
+	EntryMisc          EntryType = "misc"
+	EntryPhDThesis     EntryType = "phdthesis"
+	EntryProceedings   EntryType = "proceedings"
+	EntryTechReport    EntryType = "techreport"
+	EntryUnpublished   EntryType = "unpublished"
+)
+
+// The additional entry types of biblatex
+const (
+	EntryBookInBook     EntryType = "bookinbook"
+	EntryCollection     EntryType = "collection"
+	EntryDataset        EntryType = "dataset"
+	EntryInReference    EntryType = "inreference"
+	EntryMVBook         EntryType = "mvbook"
+	EntryMVCollection   EntryType = "mvcollection"
+	EntryMVProceedings  EntryType = "mvproceedings"
+	EntryMVReference    EntryType = "mvreference"
+	EntryOnline         EntryType = "online"
+	EntryPatent         EntryType = "patent"
+	EntryPeriodical     EntryType = "periodical"
+	EntryReference      EntryType = "reference"
+	EntryReport         EntryType = "report"
+	EntrySet            EntryType = "set"
+	EntrySoftware       EntryType = "software"
+	EntrySuppBook       EntryType = "suppbook"
+	EntrySuppCollection EntryType = "suppcollection"
+	EntrySuppPeriodical EntryType = "suppperiodical"
+	EntryThesis         EntryType = "thesis"
+	EntryXData          EntryType = "xdata"
+)
+
+// The special blocks of BibTeX, which are no bibliographic entries (and not returned by
+// ParseEntryTypeName)
+const (
+	EntryComment  EntryType = "comment"
+	EntryPreamble EntryType = "preamble"
+	EntryString   EntryType = "string"
+)
+
+// entryTypes contains the EntryType constants of the bibliographic entry types.
+var entryTypes = map[EntryType]bool{
+	EntryArticle: true, EntryBook: true, EntryBooklet: true, EntryConference: true, EntryInBook: true,
+	EntryInCollection: true, EntryInProceedings: true, EntryManual: true, EntryMastersThesis: true,
+	EntryMisc: true, EntryPhDThesis: true, EntryProceedings: true, EntryTechReport: true,
+	EntryUnpublished: true, EntryBookInBook: true, EntryCollection: true, EntryDataset: true, EntryInReference: true,
+	EntryMVBook: true, EntryMVCollection: true, EntryMVProceedings: true, EntryMVReference: true,
+	EntryOnline: true, EntryPatent: true, EntryPeriodical: true, EntryReference: true,
+	EntryReport: true, EntrySet: true, EntrySoftware: true, EntrySuppBook: true,
+	EntrySuppCollection: true, EntrySuppPeriodical: true, EntryThesis: true, EntryXData: true,
+}
+
+// ParseEntryTypeName returns the EntryType of the entry type name, ignoring the case and
+// surrounding white spaces (e.g., EntryInProceedings for InProceedings). It returns false
+// if the name is not one of the EntryType constants.
+func ParseEntryTypeName(name string) (EntryType, bool) {
+	entryType := EntryType(strings.ToLower(strings.TrimSpace(name)))
+	if !entryTypes[entryType] {
+		return "", false
+	}
+	return entryType, true
+}
+
+// TypedType returns the EntryType of the entry, see ParseEntryTypeName. The EntryType
+// field keeps the name as parsed, so entries of other types can still be processed.
+func (e *Entry) TypedType() (EntryType, bool) {
+	return ParseEntryTypeName(e.EntryType)
+}
@@ -0,0 +1,36 @@
+// Unit-tests for entrytype.go
+package parser
+
+import "testing"
+
+func TestParseEntryTypeName(t *testing.T) {
+	// Case 1: Known entry types, ignoring the case
+	cases := map[string]EntryType{
+		"article": EntryArticle, "InProceedings": EntryInProceedings, " PHDTHESIS ": EntryPhDThesis,
+		"online": EntryOnline, "MVBook": EntryMVBook, "set": EntrySet,
+	}
+	for name, expected := range cases {
+		if entryType, ok := ParseEntryTypeName(name); !ok || expected != entryType {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, entryType)
+		}
+	}
+	// Case 2: Unknown entry types
+	for _, name := range []string{"", "string", "artikel", "in proceedings"} {
+		if entryType, ok := ParseEntryTypeName(name); ok {
+			t.Errorf("Expected no entry type for '%s', but got '%#v'", name, entryType)
+		}
+	}
+}
+
+func TestTypedType(t *testing.T) {
+	// Case 1: The parsed entry type keeps its name, but has a typed counterpart
+	entry, _ := ParseNewEntry(`@InBook{a, title = {A}}`)
+	if entryType, ok := entry.TypedType(); !ok || entryType != EntryInBook || entry.RawEntryType != "InBook" {
+		t.Errorf("Expected '%#v', but got '%#v'", EntryInBook, entryType)
+	}
+	// Case 2: Custom entry types
+	entry, _ = ParseNewEntry(`@custom{b, title = {B}}`)
+	if entryType, ok := entry.TypedType(); ok {
+		t.Errorf("Expected no entry type, but got '%#v'", entryType)
+	}
+}
@@ -7,336 +7,1427 @@
 package parser
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"regexp"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // Define errors
 type ErrParsingEntry struct {
 	Message string
+	Line    int // The line of the problem in the BibTeX file (0 if unknown).
+}
+
+type ErrUnbalancedBraces struct {
+	Position int  // The index of the unmatched brace in the clean entry (see Entry.CleanEntry).
+	Line     int  // The line of the unmatched brace in the BibTeX file (0 if unknown).
+	Unclosed bool // True if a '{' is never closed, false if a '}' has no matching '{'.
+}
+
+type ErrMismatchedDelimiters struct {
+	Field string
+	Open  rune   // The first char of the value, '{' or '"'.
+	Close rune   // The last char of the value, the other one of '}' and '"'.
+	Value string // The value including its delimiters.
+	Line  int    // The line of the value in the BibTeX file (0 if unknown).
 }
 
 type ErrEmptyString struct {
 	Message string
 }
 
+type ErrUndefinedMacro struct {
+	Name string
+}
+
+type ErrInvalidKey struct {
+	Key     string
+	BadChar rune // The first char of the key that is not allowed.
+}
+
+type ErrInFile struct {
+	Path string // The path of the BibTeX file passed to ParseFiles.
+	Err  error  // The error of the file.
+}
+
+type ErrMissingComma struct {
+	Key   string
+	Field string // The field that is not separated from the previous value by a ','.
+}
+
+type ErrExtraComma struct {
+	Key   string
+	Field string // The field after which the extra ',' appears ("" if it follows the key).
+}
+
+type ErrMissingClosingBrace struct {
+	Key  string
+	Line int // The last line of the entry in the BibTeX file (0 if unknown).
+}
+
 func (e *ErrParsingEntry) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("Error parsing a BibTeX entry (line %d): %s", e.Line, e.Message)
+	}
 	return fmt.Sprintf("Error parsing a BibTeX entry: %s", e.Message)
 }
 
+func (e *ErrUnbalancedBraces) Error() string {
+	problem := fmt.Sprintf("unmatched '}' at position %d", e.Position)
+	if e.Unclosed {
+		problem = fmt.Sprintf("'{' at position %d is never closed", e.Position)
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("Error parsing a BibTeX entry (line %d): unbalanced braces, %s", e.Line, problem)
+	}
+	return fmt.Sprintf("Error parsing a BibTeX entry: unbalanced braces, %s", problem)
+}
+
+func (e *ErrMismatchedDelimiters) Error() string {
+	problem := fmt.Sprintf("value of field '%s' starts with '%c', but ends with '%c': %s", e.Field, e.Open, e.Close, e.Value)
+	if e.Line > 0 {
+		return fmt.Sprintf("Error parsing a BibTeX entry (line %d): %s", e.Line, problem)
+	}
+	return fmt.Sprintf("Error parsing a BibTeX entry: %s", problem)
+}
+
 func (e *ErrEmptyString) Error() string {
 	return fmt.Sprintf("Error processing a BibTeX entry: %s", e.Message)
 }
 
-// Debug logger
-var debugLog = log.New(os.Stdout, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile)
+func (e *ErrUndefinedMacro) Error() string {
+	return fmt.Sprintf("Error parsing a BibTeX entry: undefined @string macro '%s'", e.Name)
+}
+
+func (e *ErrInvalidKey) Error() string {
+	return fmt.Sprintf("Error parsing a BibTeX entry: key '%s' contains the invalid character %q", e.Key, e.BadChar)
+}
+
+func (e *ErrInFile) Error() string {
+	return fmt.Sprintf("%s (file %s)", e.Err, e.Path)
+}
+
+func (e *ErrInFile) Unwrap() error {
+	return e.Err
+}
+
+func (e *ErrMissingComma) Error() string {
+	return fmt.Sprintf("Warning parsing BibTeX entry '%s': missing ',' before field '%s'", e.Key, e.Field)
+}
+
+func (e *ErrExtraComma) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("Warning parsing BibTeX entry '%s': extra ',' after the key", e.Key)
+	}
+	return fmt.Sprintf("Warning parsing BibTeX entry '%s': extra ',' after field '%s'", e.Key, e.Field)
+}
+
+func (e *ErrMissingClosingBrace) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("Warning parsing BibTeX entry '%s' (line %d): the closing '}' of the entry is missing and was added", e.Key, e.Line)
+	}
+	return fmt.Sprintf("Warning parsing BibTeX entry '%s': the closing '}' of the entry is missing and was added", e.Key)
+}
 
 // Package vars
 var regexRemoveWhiteSpace = regexp.MustCompile(`\s{2,}`)
 
-// Regex to remove comments in BibTeX entry starting with %
-// Should not remove escaped percentages like \%
-// We also assume that the there is a whitespace between % <COMMENT> to avoid
-// deleting parts of URLs
-var regexRemoveComments = regexp.MustCompile(`(^|[^\\])%\s[^\n\r]*`)
+// Regex to find line breaks including their surrounding white spaces
+var regexLineBreak = regexp.MustCompile(`[ \t]*[\n\r]\s*`)
 
-// Regex to find all valid field names
-var regexFindFieldNames = regexp.MustCompile(`([a-zA-Z\s]+)=(?:\s*[{"]+)`)
+// Regex to find line breaks, tabs, and carriage returns
+var regexControlChars = regexp.MustCompile(`[\n\r\t]`)
 
-// Regex to find BibTeX entry ID
-var regexFindID = regexp.MustCompile(`(^|,)\s*[a-zA-Z.-:_0-9]+\s*(,|$)`)
+// Regex to match the beginning of a field (e.g., title =), see findMissingComma
+var regexFieldStart = regexp.MustCompile(`^[a-zA-Z_:.-][a-zA-Z0-9_:.-]*\s*=`)
+
+// Regex to match a whole field name, see findFieldName
+var regexFieldName = regexp.MustCompile(`^[a-zA-Z_:.-][a-zA-Z0-9_:.-]*$`)
+
+// Regex to match bare numbers in field values
+var regexBareNumber = regexp.MustCompile(`^[0-9]+$`)
+
+// Regex to match valid @string macro names
+var regexMacroName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.:+-]*$`)
+
+// Predefined macros for the month names, as provided by the standard BibTeX styles
+var defaultMacros = map[string]string{
+	"jan": "January",
+	"feb": "February",
+	"mar": "March",
+	"apr": "April",
+	"may": "May",
+	"jun": "June",
+	"jul": "July",
+	"aug": "August",
+	"sep": "September",
+	"oct": "October",
+	"nov": "November",
+	"dec": "December",
+}
+
+// Regex to find @comment and @preamble blocks, which are no bibliographic entries
+var regexSpecialBlock = regexp.MustCompile(`(?i)^\s*@\s*(comment|preamble)\s*[{(]`)
+
+// Regex to find the entry type, which is followed by the opening '{' or '(' of the entry body
+var regexEntryType = regexp.MustCompile(`^@\s*([a-zA-Z0-9_:-]+)\s*[{(]`)
+
+// Regex to find a field value opened by '{' and closed by '"', followed by the next field or the end of the entry
+var regexMismatchedDelimiters = regexp.MustCompile(`([a-zA-Z0-9_:.-]+)\s*=\s*(\{[^{}"]*[^{}"\\]")\s*(?:,\s*[a-zA-Z0-9_:.-]+\s*=|,?\s*\}\s*$)`)
+
+// Regex to match a BibTeX entry ID (letters, digits, and .-/:_)
+var regexFindID = regexp.MustCompile(`^[a-zA-Z0-9./:_-]+$`)
 
 // Entry represents a bibliographic entry in a BibTeX file.
 // It contains the type of the entry (e.g., article, book),
 // a unique key to identify the entry, the raw entry string,
 // and a map of fields with their corresponding values.
 type Entry struct {
-	EntryType  string            // The type of the entry (e.g., article, book).
-	Key        string            // A unique key to identify the entry.
-	RawEntry   string            // The raw entry string in BibTeX format.
-	CleanEntry string            // The cleaned raw BibTeX input (RawEntry).
-	Fields     map[string]string // A map of fields and their corresponding values.
+	EntryType       string            // The lowercased type of the entry (e.g., article, book).
+	RawEntryType    string            // The type of the entry as written in the BibTeX file (e.g., Article).
+	Key             string            // A unique key to identify the entry.
+	RawEntry        string            // The raw entry string in BibTeX format.
+	CleanEntry      string            // The cleaned raw BibTeX input (RawEntry).
+	Fields          map[string]string // A map of fields and their corresponding values.
+	RawFieldNames   map[string]string // The field names as written in the BibTeX file (e.g., SERIES), keyed by the lowercased names.
+	DuplicateFields []string          // The lowercased names of fields that appear more than once (only the last value is kept).
+	ParseWarnings   []error           // Tolerated syntax problems, e.g., an ErrMissingComma between two fields.
+	SourceFile      string            // The path of the BibTeX file of the entry (set by ParseFiles).
+	Line            int               // The line of the entry's '@' in the parsed input (0 for ParseNewEntry).
+	StartOffset     int               // The byte offset of the entry's '@' in the parsed input (0 for ParseNewEntry).
+	EndOffset       int               // The byte offset after the entry, so input[StartOffset:EndOffset] is the RawEntry.
+	FieldPositions  map[string]int    // The byte offsets of the field names in RawEntry, keyed by the lowercased names (see ParseOptions.FieldPositions).
+
+	macroValues map[string]macroValue // The values written with @string macros, keyed by the lowercased names, see Format.
+}
+
+// macroValue is a field value written with @string macros, e.g., jan or acm # " Press".
+type macroValue struct {
+	Expression string // The value as written in the BibTeX file.
+	Value      string // The expanded value; the Expression is only written while the field still has this value.
 }
 
 // BibTeXFile represents a BibTeX file with its associated metadata.
-// It contains the file path, name, and a list of entries.
+// It contains the file path, name, a list of entries, the @string macros,
+// the @comment and @preamble blocks, the disabled entries inside of @comment blocks,
+// the stray text and % comments between the entries, and the errors that occurred while
+// parsing the entries.
 type BibTeXFile struct {
-	FilePath string   // The file path of the BibTeX file.
-	Entries  []*Entry // A slice of Entry structs representing the entries in the BibTeX file.
+	FilePath        string            // The file path of the BibTeX file.
+	Entries         []*Entry          // A slice of Entry structs representing the entries in the BibTeX file.
+	Macros          map[string]string // The @string macro definitions of the BibTeX file (lowercased names).
+	Errors          []error           // All errors that occurred while parsing the entries.
+	Comments        []string          // The contents of all @comment blocks.
+	Preamble        string            // The contents of the @preamble blocks (joined by " # ").
+	DisabledEntries []*Entry          // The entries wrapped in @comment blocks to disable them temporarily.
+	StrayText       []StrayText       // The text between the entries that is neither white space nor a % comment.
+	TeXComments     []TeXComment      // The % comments between the entries.
+
+	keyIndex       map[string]*Entry // Lazily built index of Entries by their Key, see LookupByKey.
+	commentIndices []int             // The number of Entries before each of the Comments when it was parsed, see WriteToWithOptions.
+}
+
+// ParseOptions control the optional processing steps of ParseNewEntryWithOptions
+// and ParseNewBibTeXFileWithOptions. The zero value keeps the field values as they are
+// and collects all errors.
+type ParseOptions struct {
+	// Normalize all field values to the Unicode normalization form NFC, so that composed
+	// and decomposed characters (e.g., ü as one or two code points) compare equal.
+	NormalizeNFC bool
+	// Stop parsing the file at the first entry with an error instead of collecting the
+	// errors of all entries, see ParseNewBibTeXFileWithOptions.
+	FailFast bool
+	// Record the offset of each field name in Entry.FieldPositions, e.g., to mark a
+	// malformed field in an editor. It is off by default, as it adds a map per entry.
+	FieldPositions bool
+	// Add the closing '}' of the last entry of the file if it is the only brace that is never
+	// closed, e.g., of a truncated file, instead of rejecting the entry. The repair is
+	// reported as an ErrMissingClosingBrace in the ParseWarnings of the entry. Entries
+	// missing their '}' in the middle of the file are still rejected.
+	RepairMissingBrace bool
 }
 
 // ParseNewBibTeXFile takes a Reader object and tries to parse entries from it.
+// The input is read entry by entry using an EntryScanner, so large files are not
+// loaded into memory as a whole. A UTF-8 byte order mark at the start of the input
+// (written by some editors) is skipped.
+// @string definitions are collected in Macros and not added to Entries. Macros can
+// be referenced by field values of all entries following their definition.
+// The contents of @comment and @preamble blocks are stored in Comments and Preamble.
+// Entries inside of @comment blocks are additionally parsed into DisabledEntries.
+// Errors of single entries do not stop the parsing, but are collected in the Errors of the
+// returned BibTeXFile. The returned error is only non-nil if reading from r fails.
 func ParseNewBibTeXFile(r io.Reader) (*BibTeXFile, error) {
-	scanner := bufio.NewScanner(r)
-	// Creating a re to find the beginning of a BibTeX entry
-	re, err := regexp.Compile(`^\s*@`)
-	if err != nil {
+	return ParseNewBibTeXFileWithOptions(r, ParseOptions{})
+}
+
+// ParseNewBibTeXFileWithOptions parses a BibTeX file like ParseNewBibTeXFile and
+// runs the processing steps enabled in opts on all entries.
+// With opts.FailFast, the parsing stops at the first entry with an error, and its first
+// error is returned together with the entries parsed so far (the error is also in Errors).
+func ParseNewBibTeXFileWithOptions(r io.Reader, opts ParseOptions) (*BibTeXFile, error) {
+	return parseBibTeXFile(context.Background(), r, opts)
+}
+
+// ParseNewBibTeXFileContext parses a BibTeX file like ParseNewBibTeXFile, but stops early
+// if ctx is canceled or its deadline is exceeded, e.g., to bound the time spent on files
+// uploaded by users. The context is checked between entries; if it is done, no BibTeXFile
+// is returned and the error is ctx.Err() (context.Canceled or context.DeadlineExceeded).
+func ParseNewBibTeXFileContext(ctx context.Context, r io.Reader) (*BibTeXFile, error) {
+	return parseBibTeXFile(ctx, r, ParseOptions{})
+}
+
+// parseBibTeXFile parses a BibTeX file with the options, see ParseNewBibTeXFileWithOptions
+// and ParseNewBibTeXFileContext.
+func parseBibTeXFile(ctx context.Context, r io.Reader, opts ParseOptions) (*BibTeXFile, error) {
+	bibtexFile := BibTeXFile{Macros: make(map[string]string)}
+	if err := bibtexFile.addEntriesFrom(ctx, r, opts); err != nil {
 		return nil, err
 	}
-	bibtexFile := BibTeXFile{}
-	entryCounter := 1
-	var stringBuffer []string
+	if opts.FailFast && len(bibtexFile.Errors) > 0 {
+		return &bibtexFile, bibtexFile.Errors[0]
+	}
+	return &bibtexFile, nil
+}
+
+// ParseFiles parses several BibTeX files into one BibTeXFile, e.g., if the references of
+// a project are split across files. The files are concatenated in the given order like by
+// BibTeX, so @string macros of a file can be used by the following files. The SourceFile of
+// every entry is set to its path, and the parsing errors in the Errors of the returned file
+// are wrapped in an ErrInFile with the path. Entries with the same key in different files are
+// all kept, so they are reported by DuplicateKeys. The FilePath of the returned file is empty.
+// Files that cannot be read are skipped; their errors are returned.
+func ParseFiles(paths ...string) (*BibTeXFile, []error) {
+	bibtexFile := &BibTeXFile{Macros: make(map[string]string)}
+	var errs []error
+	for _, path := range paths {
+		if err := bibtexFile.addEntriesFromFile(path, ParseOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return bibtexFile, errs
+}
+
+// addEntriesFromFile adds the entries of the BibTeX file at path (see ParseFiles).
+func (f *BibTeXFile) addEntriesFromFile(path string, opts ParseOptions) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	entryCount, disabledCount, errorCount, strayCount := len(f.Entries), len(f.DisabledEntries), len(f.Errors), len(f.StrayText)
+	err = f.addEntriesFrom(context.Background(), file, opts)
+	for _, entry := range f.Entries[entryCount:] {
+		entry.SourceFile = path
+	}
+	for _, entry := range f.DisabledEntries[disabledCount:] {
+		entry.SourceFile = path
+	}
+	for i := strayCount; i < len(f.StrayText); i++ {
+		f.StrayText[i].SourceFile = path
+	}
+	for i := errorCount; i < len(f.Errors); i++ {
+		f.Errors[i] = &ErrInFile{Path: path, Err: f.Errors[i]}
+	}
+	if err != nil {
+		return &ErrInFile{Path: path, Err: err}
+	}
+	return nil
+}
+
+// addEntriesFrom reads all raw entries from r and adds them to the BibTeXFile, see addRawEntry.
+// With opts.FailFast, it stops after the first entry with an error.
+// The returned error is only non-nil if reading from r fails or ctx is done before an entry.
+func (f *BibTeXFile) addEntriesFrom(ctx context.Context, r io.Reader, opts ParseOptions) error {
+	scanner := NewEntryScanner(r)
+	errorCount := len(f.Errors)
 	for scanner.Scan() {
-		line := scanner.Text()
-		if re.MatchString(line) {
-			// Resetting stringBuffer
-			if len(stringBuffer) > 0 {
-				rawEntry := strings.Join(stringBuffer, " ")
-				// Try to parse entry
-				entry, err := ParseNewEntry(rawEntry)
-				if err != nil {
-					fmt.Printf("Something went wrong when parsing entry no. %d\n", entryCounter)
-				} else {
-					bibtexFile.Entries = append(bibtexFile.Entries, entry)
-				}
-				stringBuffer = nil
-				entryCounter += 1
-			}
-			// Add line
-			stringBuffer = append(stringBuffer, line)
-			continue
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-		// Add line if buffer is not empty
-		if len(stringBuffer) > 0 {
-			stringBuffer = append(stringBuffer, line)
+		f.StrayText = append(f.StrayText, scanner.StrayText()...)
+		f.TeXComments = append(f.TeXComments, scanner.TeXComments()...)
+		// Try to parse entry
+		start, end := scanner.Offsets()
+		f.addRawEntry(scanner.Text(), scanner.Line(), start, end, opts)
+		if opts.FailFast && len(f.Errors) > errorCount {
+			return nil
 		}
 	}
+	f.StrayText = append(f.StrayText, scanner.StrayText()...)
+	f.TeXComments = append(f.TeXComments, scanner.TeXComments()...)
+	return scanner.Err()
+}
 
-	// Check if there is a remaining entry in buffer
-	if len(stringBuffer) > 0 {
-		rawEntry := strings.Join(stringBuffer, " ")
-		// Try to parse entry
-		entry, err := ParseNewEntry(rawEntry)
-		if err != nil {
-			fmt.Printf("Something went wrong when parsing entry no. %d\n", entryCounter)
+// ParseString parses BibTeX text that is already in memory like ParseNewBibTeXFile,
+// e.g., in tests or small tools. The text may contain multiple concatenated entries,
+// @string macros, and @comment or @preamble blocks. To parse a single entry into an
+// Entry, see ParseNewEntry.
+func ParseString(s string) (*BibTeXFile, error) {
+	return ParseNewBibTeXFile(strings.NewReader(s))
+}
+
+// addRawEntry parses a raw BibTeX entry starting at the given line and byte range of the
+// input and adds it to the BibTeXFile.
+// @string definitions are added to the file's Macros instead of its Entries.
+// @comment and @preamble blocks are added to the file's Comments and Preamble.
+// Parsing errors are added to the file's Errors.
+func (f *BibTeXFile) addRawEntry(rawEntry string, line, start, end int, opts ParseOptions) {
+	if match := regexSpecialBlock.FindStringSubmatch(rawEntry); match != nil {
+		content := blockContent(rawEntry)
+		if EntryType(strings.ToLower(match[1])) == EntryComment {
+			f.Comments = append(f.Comments, content)
+			f.commentIndices = append(f.commentIndices, len(f.Entries))
+			f.addDisabledEntries(content, opts)
+		} else if f.Preamble == "" {
+			f.Preamble = content
 		} else {
-			bibtexFile.Entries = append(bibtexFile.Entries, entry)
+			f.Preamble += " # " + content
 		}
+		return
 	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, scanner.Err()
+	entry, errs := parseEntry(rawEntry, f.Macros, line, opts)
+	f.Errors = append(f.Errors, errs...)
+	if entry == nil {
+		return
 	}
+	entry.StartOffset, entry.EndOffset = start, end
+	if EntryType(entry.EntryType) == EntryString {
+		for name, value := range entry.Fields {
+			f.Macros[name] = value
+		}
+		return
+	}
+	f.Entries = append(f.Entries, entry)
+	f.keyIndex = nil
+}
 
-	return &bibtexFile, nil
+// addDisabledEntries adds the entries inside of a @comment block (e.g., @comment{@article{...}})
+// to the DisabledEntries. Other text of the comment, @string and
+// other special blocks, and the errors of the disabled entries are ignored.
+func (f *BibTeXFile) addDisabledEntries(comment string, opts ParseOptions) {
+	if !strings.Contains(comment, "@") {
+		return
+	}
+	rawEntries, _ := SplitEntries(strings.NewReader(comment))
+	for _, rawEntry := range rawEntries {
+		if regexSpecialBlock.MatchString(rawEntry) {
+			continue
+		}
+		entry, _ := parseEntry(rawEntry, f.Macros, 0, opts)
+		if entry != nil && EntryType(entry.EntryType) != EntryString {
+			f.DisabledEntries = append(f.DisabledEntries, entry)
+		}
+	}
 }
 
 // ParseNewEntry parses a raw string in BibTeX format and tries to create an Entry struct.
 // The expected format of the RawEntry string is a valid BibTeX entry, which includes the entry type,
 // a unique key, and a set of fields with their corresponding values. The function cleans the raw entry
 // by removing unnecessary white spaces and line breaks, and then checks if the cleaned entry is empty.
-// ParseNewEntry also gracefull removes TeX comments starting with % (also using % for comments in BibTeX should generally be avoided).
+// ParseNewEntry also gracefull removes TeX comments starting with % outside of field values (also using % for comments in BibTeX should generally be avoided).
 // If the cleaned entry is not empty, it returns a new Entry struct with the raw entry string.
+// If only the fields or the key of the entry cannot be parsed, the partially parsed Entry is
+// returned together with an error joining all problems (see errors.Join); a field with a
+// rejected value (e.g., an undefined macro) is left out, but all other fields are kept.
+// Only the predefined month macros (jan, feb, ...) can be referenced by field values, see ParseNewBibTeXFile.
 func ParseNewEntry(RawEntry string) (*Entry, error) {
+	return ParseNewEntryWithOptions(RawEntry, ParseOptions{})
+}
+
+// ParseNewEntryWithOptions parses a raw string in BibTeX format like ParseNewEntry and
+// runs the processing steps enabled in opts (e.g., NFC normalization of the field values).
+func ParseNewEntryWithOptions(RawEntry string, opts ParseOptions) (*Entry, error) {
+	entry, errs := parseEntry(RawEntry, nil, 0, opts)
+	if entry != nil {
+		entry.EndOffset = len(RawEntry)
+	}
+	return entry, errors.Join(errs...)
+}
+
+// parseEntry parses a raw string in BibTeX format, resolving bare field values
+// against the given @string macros and running the processing steps of opts. See ParseNewEntry.
+// The line where the entry begins in the BibTeX file is added to all ErrParsingEntry errors.
+// The returned Entry is nil if the entry type cannot be parsed.
+func parseEntry(RawEntry string, macros map[string]string, line int, opts ParseOptions) (*Entry, []error) {
+	repaired := false
+	if opts.RepairMissingBrace {
+		RawEntry, repaired = repairMissingBrace(RawEntry)
+	}
 	newEntry := &Entry{
 		RawEntry: RawEntry,
+		Line:     line,
 	}
 	// Clean raw entry for processing
-	cleanEntry := cleanRawEntry(RawEntry)
+	cleanEntry, origins := cleanRawEntryWithOrigins(RawEntry)
+	lines := originLines(RawEntry, origins)
 	// Check if entry is empty
 	if len(cleanEntry) == 0 {
-		return nil, &ErrParsingEntry{Message: "Entry is empty after cleaning."}
+		return nil, []error{&ErrParsingEntry{Message: "Entry is empty after cleaning.", Line: line}}
 	}
 	newEntry.CleanEntry = cleanEntry
+	// Check braces before parsing the structure of the entry
+	if err := checkBraceBalance(cleanEntry); err != nil {
+		var unbalanced *ErrUnbalancedBraces
+		if errors.As(err, &unbalanced) && unbalanced.Unclosed {
+			// A value like {value" leaves its '{' unclosed
+			if mismatched, position := findMismatchedDelimiters(cleanEntry); mismatched != nil {
+				if line > 0 {
+					mismatched.Line = line + lines[position] - 1
+				}
+				return nil, []error{mismatched}
+			}
+		}
+		if line > 0 && errors.As(err, &unbalanced) {
+			unbalanced.Line = line + lines[unbalanced.Position] - 1
+		}
+		return nil, []error{err}
+	}
 	// Parse entry type
-	entryType, err := parseEntryType(cleanEntry)
+	rawEntryType, err := parseRawEntryType(cleanEntry)
 	if err != nil {
-		return nil, err
+		return nil, []error{withLine(err, line)}
 	}
+	entryType := strings.ToLower(rawEntryType)
 	newEntry.EntryType = entryType
+	newEntry.RawEntryType = rawEntryType
+	var errs []error
 	// Parse fields
-	newEntry.Fields, err = parseFields(cleanEntry)
+	var positions map[string]int
+	if opts.FieldPositions {
+		positions = make(map[string]int)
+	}
+	expressions := make(map[string]string)
+	fields, rawFieldNames, duplicates, separatorWarnings, rejected, err := parseFieldsWithOffset(cleanEntry, macros, positions, expressions)
+	newEntry.Fields = fields
+	newEntry.RawFieldNames = rawFieldNames
+	newEntry.DuplicateFields = duplicates
+	newEntry.ParseWarnings = separatorWarnings
+	if repaired {
+		repairErr := &ErrMissingClosingBrace{}
+		if line > 0 {
+			// The '}' is added in a new line after the last line of the entry
+			repairErr.Line = line + strings.Count(RawEntry, "\n") - 1
+		}
+		newEntry.ParseWarnings = append(newEntry.ParseWarnings, repairErr)
+	}
 	if err != nil {
-		debugLog.Println(err)
+		newEntry.Fields = make(map[string]string)
+		newEntry.RawFieldNames = make(map[string]string)
+		errs = append(errs, withLine(err, line))
+	}
+	// Only the rejected values are left out, reported with their own line
+	for _, value := range rejected {
+		valueLine := line
+		if line > 0 && value.offset >= 0 && value.offset < len(lines) {
+			valueLine += lines[value.offset] - 1
+		}
+		errs = append(errs, withLine(value.err, valueLine))
+	}
+	if err == nil && positions != nil {
+		// Map the offsets in the clean entry to the raw entry
+		for fieldName, position := range positions {
+			positions[fieldName] = origins[position]
+		}
+		newEntry.FieldPositions = positions
+	}
+	if opts.NormalizeNFC {
+		for fieldName, value := range newEntry.Fields {
+			newEntry.Fields[fieldName] = norm.NFC.String(value)
+		}
+	}
+	if err == nil && len(expressions) > 0 {
+		newEntry.macroValues = make(map[string]macroValue, len(expressions))
+		for fieldName, expression := range expressions {
+			newEntry.macroValues[fieldName] = macroValue{Expression: expression, Value: newEntry.Fields[fieldName]}
+		}
+	}
+	// @string definitions do not have an ID
+	if EntryType(entryType) == EntryString {
+		return newEntry, errs
 	}
 	// Parse ID
 	newEntry.Key, err = parseID(cleanEntry)
 	if err != nil {
-		debugLog.Println(err)
+		errs = append(errs, withLine(err, line))
+	}
+	for _, warning := range newEntry.ParseWarnings {
+		switch warning := warning.(type) {
+		case *ErrMissingComma:
+			warning.Key = newEntry.Key
+		case *ErrExtraComma:
+			warning.Key = newEntry.Key
+		case *ErrMissingClosingBrace:
+			warning.Key = newEntry.Key
+		}
+	}
+	return newEntry, errs
+}
+
+// SetField sets the value of the field with the given name. The name is trimmed and
+// lowercased like the field names of parsed entries (e.g., " Title " becomes title), and
+// the trimmed name is kept as the raw field name, see RawFieldName.
+// Use SetField instead of writing to Fields directly to keep the field names normalized.
+func (e *Entry) SetField(name, value string) {
+	rawFieldName := strings.TrimSpace(name)
+	fieldName := strings.ToLower(rawFieldName)
+	if e.Fields == nil {
+		e.Fields = make(map[string]string)
+	}
+	if e.RawFieldNames == nil {
+		e.RawFieldNames = make(map[string]string)
+	}
+	e.Fields[fieldName] = value
+	e.RawFieldNames[fieldName] = rawFieldName
+}
+
+// GetField returns the value of the field with the given name, which is trimmed and
+// lowercased like in SetField (e.g., GetField("Title") returns the title field).
+// It returns false if the entry has no such field.
+func (e *Entry) GetField(name string) (string, bool) {
+	value, ok := e.Fields[strings.ToLower(strings.TrimSpace(name))]
+	return value, ok
+}
+
+// DeleteField removes the field with the given name, which is trimmed and lowercased like
+// in SetField, together with its raw field name. It returns false if the entry has no such field.
+func (e *Entry) DeleteField(name string) bool {
+	fieldName := strings.ToLower(strings.TrimSpace(name))
+	if _, ok := e.Fields[fieldName]; !ok {
+		return false
 	}
-	return newEntry, nil
+	delete(e.Fields, fieldName)
+	delete(e.FieldPositions, fieldName)
+	delete(e.RawFieldNames, fieldName)
+	return true
 }
 
 // Helper functions
 
+// withLine adds the given line to an ErrParsingEntry error.
+// Other errors are returned unchanged.
+func withLine(err error, line int) error {
+	var parsingErr *ErrParsingEntry
+	if line > 0 && errors.As(err, &parsingErr) {
+		parsingErr.Line = line
+	}
+	var mismatchedErr *ErrMismatchedDelimiters
+	if line > 0 && errors.As(err, &mismatchedErr) {
+		mismatchedErr.Line = line
+	}
+	return err
+}
+
 // cleanRawEntry tries to clean a BibTeX raw string.
 // Stripping the text of unnecessary white spaces and line breaks.
 func cleanRawEntry(input string) string {
+	cleanEntry, _ := cleanRawEntryWithMap(input)
+	return cleanEntry
+}
+
+// cleanRawEntryWithMap cleans a BibTeX raw string like cleanRawEntry and additionally
+// returns an offset table, which maps each byte of the cleaned string to the line
+// (starting at 1) of the raw string it comes from.
+func cleanRawEntryWithMap(input string) (string, []int) {
+	cleanEntry, origins := cleanRawEntryWithOrigins(input)
+	return cleanEntry, originLines(input, origins)
+}
+
+// cleanRawEntryWithOrigins cleans a BibTeX raw string like cleanRawEntry and additionally
+// returns an offset table, which maps each byte of the cleaned string to the offset of the
+// byte in the raw string it comes from.
+// Line breaks and their surrounding white spaces are replaced with a single white space
+// to keep words of multi-line values apart. Line breaks at the beginning of the string or
+// after one of the structural chars ',', '{' or '}' are removed entirely.
+func cleanRawEntryWithOrigins(input string) (string, []int) {
 	// Trim leading and trailing white spaces
-	trimmed := strings.TrimSpace(input)
-	// Remove % comments
-	oneLine := regexRemoveComments.ReplaceAllString(trimmed, "")
+	start := len(input) - len(strings.TrimLeftFunc(input, unicode.IsSpace))
+	end := max(len(strings.TrimRightFunc(input, unicode.IsSpace)), start)
+	text := &mappedText{text: input[start:end], origins: make([]int, 0, end-start)}
+	for i := start; i < end; i++ {
+		text.origins = append(text.origins, i)
+	}
+	// Remove % comments outside of field values
+	text.replace(findComments(text.text), func(match []int) string {
+		return ""
+	})
+	// Join lines without gluing together words of multi-line values
+	text.replaceAll(regexLineBreak, func(match []int) string {
+		if match[0] > 0 && !strings.ContainsRune(",{}", rune(text.text[match[0]-1])) {
+			return " "
+		}
+		return ""
+	})
 	// Remove line breaks, tabs, and carriage returns
-	replacer := strings.NewReplacer("\n", "", "\r", "", "\t", "")
-	oneLine = replacer.Replace(oneLine)
+	text.replaceAll(regexControlChars, func(match []int) string {
+		return ""
+	})
 	// Replace multiple white spaces with single white space
-	oneLine = regexRemoveWhiteSpace.ReplaceAllString(oneLine, " ")
-	return oneLine
+	text.replaceAll(regexRemoveWhiteSpace, func(match []int) string {
+		return " "
+	})
+	return text.text, text.origins
 }
 
-// parseEntryType parses the entry type of a BibTeX entry string.
-func parseEntryType(bibtexEntry string) (string, error) {
-	if len(bibtexEntry) == 0 {
-		return "", &ErrEmptyString{Message: "The string is empty."}
+// originLines maps the offsets in the raw string (see cleanRawEntryWithOrigins) to their
+// lines, starting at 1.
+func originLines(input string, origins []int) []int {
+	lines := make([]int, len(origins))
+	line, offset := 1, 0
+	for i, origin := range origins {
+		for ; offset < origin; offset++ {
+			if input[offset] == '\n' {
+				line++
+			}
+		}
+		lines[i] = line
 	}
-	// Split on the first appearing '{'
-	substringList := strings.Split(bibtexEntry, "{")
-	entryType, ok := safeGet(substringList, 0)
-	if !ok {
-		return "", &ErrParsingEntry{Message: fmt.Sprintf("Could not split entry on '{': %s", bibtexEntry)}
+	return lines
+}
+
+// findComments returns the start and end index of all % comments in the raw entry, which
+// end before the next line break. A % only starts a comment outside of field values, so
+// a % inside of braces or quotes (e.g., url = {http://x?a=1%20b}) and escaped
+// percentages like \% are kept.
+func findComments(s string) [][]int {
+	var comments [][]int
+	depth := 0
+	// The brace depth of the fields, which is 1 if the entry body is opened with '{'
+	// and 0 if it is opened with '('
+	fieldDepth := 0
+	inEntry, opened, inQuotes := false, false, false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			// Skip the escaped char
+			i++
+		case '@':
+			inEntry = true
+		case '{':
+			if inEntry && !opened {
+				opened = true
+				fieldDepth = 1
+			}
+			depth++
+		case '}':
+			depth--
+		case '(':
+			opened = opened || inEntry
+		case '"':
+			if opened && depth == fieldDepth {
+				inQuotes = !inQuotes
+			}
+		case '%':
+			if inQuotes || depth > fieldDepth {
+				continue
+			}
+			end := strings.IndexAny(s[i:], "\n\r")
+			if end == -1 {
+				end = len(s) - i
+			}
+			comments = append(comments, []int{i, i + end})
+			// Continue with the line break
+			i += end - 1
+		}
 	}
-	// Trim
-	trimmedEntryType := strings.TrimSpace(entryType)
-	if len(trimmedEntryType) == 0 {
+	return comments
+}
+
+// mappedText is a text together with the offset of each of its bytes in the original string.
+type mappedText struct {
+	text    string
+	origins []int
+}
+
+// replaceAll replaces all matches of the regex with the string returned by replacement,
+// which gets the submatch indices of the match (see regexp.FindAllStringSubmatchIndex).
+// The replacing chars are mapped to the offset of the first char of the match.
+func (m *mappedText) replaceAll(regex *regexp.Regexp, replacement func(match []int) string) {
+	m.replace(regex.FindAllStringSubmatchIndex(m.text, -1), replacement)
+}
+
+// replace replaces the given non-overlapping matches (start and end index, optionally followed
+// by submatch indices) in ascending order with the string returned by replacement, see replaceAll.
+func (m *mappedText) replace(matches [][]int, replacement func(match []int) string) {
+	if len(matches) == 0 {
+		return
+	}
+	var builder strings.Builder
+	builder.Grow(len(m.text))
+	origins := make([]int, 0, len(m.origins))
+	lastIndex := 0
+	for _, match := range matches {
+		builder.WriteString(m.text[lastIndex:match[0]])
+		origins = append(origins, m.origins[lastIndex:match[0]]...)
+		replaced := replacement(match)
+		builder.WriteString(replaced)
+		for range len(replaced) {
+			origins = append(origins, m.origins[min(match[0], len(m.origins)-1)])
+		}
+		lastIndex = match[1]
+	}
+	builder.WriteString(m.text[lastIndex:])
+	origins = append(origins, m.origins[lastIndex:]...)
+	m.text = builder.String()
+	m.origins = origins
+}
+
+// blockContent returns the trimmed text between the outer braces of a raw
+// block like @comment{...}. If the closing brace is missing, the remaining text is returned.
+func blockContent(rawBlock string) string {
+	openingIndex := strings.IndexAny(rawBlock, "{(")
+	if openingIndex == -1 {
+		return ""
+	}
+	content := rawBlock[openingIndex+1:]
+	closingIndex := findClosingBrace(content)
+	if rawBlock[openingIndex] == '(' {
+		closingIndex = findClosingParen(content)
+	}
+	if closingIndex != -1 {
+		content = content[:closingIndex]
+	}
+	return strings.TrimSpace(content)
+}
+
+// parseEntryType parses the entry type of a BibTeX entry string.
+// Entry types are case-insensitive, so the returned type is lowercased
+// (e.g., "article" for @Article). See parseRawEntryType for the original casing.
+func parseEntryType(bibtexEntry string) (string, error) {
+	entryType, err := parseRawEntryType(bibtexEntry)
+	return strings.ToLower(entryType), err
+}
+
+// parseRawEntryType parses the entry type of a BibTeX entry string as written in the entry.
+// The entry type has to be followed by the opening '{' of the entry body. Invisible
+// characters before the '@' are ignored, see isEntrySpace.
+func parseRawEntryType(bibtexEntry string) (string, error) {
+	// Trim, including zero-width spaces before the @
+	trimmedEntry := strings.TrimFunc(bibtexEntry, isEntrySpace)
+	if len(trimmedEntry) == 0 {
 		return "", &ErrEmptyString{Message: "The string is empty."}
 	}
 	// Check if type starts with an @
-	if trimmedEntryType[0] != '@' {
+	match := regexEntryType.FindStringSubmatch(trimmedEntry)
+	if match == nil {
 		return "", &ErrParsingEntry{Message: fmt.Sprintf("Cannot parse entry type from this entry: %s", bibtexEntry)}
 	}
-	return trimmedEntryType[1:], nil
+	return match[1], nil
+}
+
+// isEntrySpace reports whether r can be skipped before the '@' of an entry: white space
+// (including non-breaking spaces), a byte order mark, or a zero-width space or joiner,
+// which are easily pasted into a file without being visible.
+func isEntrySpace(r rune) bool {
+	switch r {
+	case '\ufeff', '\u200b', '\u200c', '\u200d', '\u2060':
+		return true
+	}
+	return unicode.IsSpace(r)
+}
+
+// entryBody returns the inner field of a clean (!) BibTeX entry.
+// Example: @article{id, author={Thomas Jurczy},...}
+// Here, the inner field is id, author={Thomas Jurczy},...
+// The entry body is delimited either by '{' and '}' or by '(' and ')' (e.g., @article(id, ...)),
+// depending on the delimiter following the entry type. The closing delimiter is the first
+// '}' (or ')') that is not part of a nested brace group, so '{', '}', and '@' inside of field
+// values are not mistaken for the delimiters of the entry. Entries without a type
+// (e.g., {id, ...}) are split on the first '{'.
+// The returned offset is the index of the inner field in the clean entry.
+func entryBody(cleanBibtexEntry string) (string, int, error) {
+	offset := len(cleanBibtexEntry) - len(strings.TrimLeftFunc(cleanBibtexEntry, isEntrySpace))
+	trimmedEntry := strings.TrimFunc(cleanBibtexEntry, isEntrySpace)
+	var innerField string
+	closing := byte('}')
+	if loc := regexEntryType.FindStringIndex(trimmedEntry); loc != nil {
+		innerField = trimmedEntry[loc[1]:]
+		offset += loc[1]
+		if trimmedEntry[loc[1]-1] == '(' {
+			closing = ')'
+		}
+	} else if strings.HasPrefix(trimmedEntry, "@") {
+		return "", -1, &ErrParsingEntry{Message: fmt.Sprintf("Could not find '{' after the entry type: %s", cleanBibtexEntry)}
+	} else {
+		openingIndex := strings.Index(trimmedEntry, "{")
+		if openingIndex == -1 {
+			return "", -1, &ErrParsingEntry{Message: fmt.Sprintf("Could not split on '{': %s", cleanBibtexEntry)}
+		}
+		innerField = trimmedEntry[openingIndex+1:]
+		offset += openingIndex + 1
+	}
+	// Check if innerField is empty
+	offset += len(innerField) - len(strings.TrimLeftFunc(innerField, unicode.IsSpace))
+	innerField = strings.TrimSpace(innerField)
+	if len(innerField) == 0 {
+		return "", -1, &ErrEmptyString{Message: "The string is empty."}
+	}
+	// Verify trailing '}' or ')'
+	if innerField[len(innerField)-1] != closing {
+		return "", -1, &ErrParsingEntry{Message: fmt.Sprintf("The last char in fields list should be '%c'.", closing)}
+	}
+	// Remove trailing '}', which is the first '}' that is not closing a nested brace group
+	closingIndex := findClosingBrace(innerField)
+	if closing == ')' {
+		closingIndex = findClosingParen(innerField)
+	}
+	if closingIndex == -1 {
+		return "", -1, &ErrParsingEntry{Message: fmt.Sprintf("Could not find the closing '%c' of the entry: %s", closing, cleanBibtexEntry)}
+	}
+	return innerField[:closingIndex], offset, nil
 }
 
 // parseFields parses all fields from a clean (!) BibTeX entry.
 // For cleaning a BibTeX entry, see cleanRawEntry().
-func parseFields(cleanBibtexEntry string) (map[string]string, error) {
+// Bare field values are resolved against the given @string macros, see parseFieldValue().
+// If a field appears more than once, the last value is kept and the lowercased field
+// name is returned in the duplicates (once per repetition, in the order of the entry).
+// Fields with rejected values are left out, and their errors are joined (see errors.Join).
+func parseFields(cleanBibtexEntry string, macros map[string]string) (map[string]string, []string, error) {
+	fieldsHashMap, _, duplicates, _, rejected, err := parseFieldsWithOffset(cleanBibtexEntry, macros, nil, nil)
+	if err != nil {
+		return fieldsHashMap, duplicates, err
+	}
+	errs := make([]error, 0, len(rejected))
+	for _, value := range rejected {
+		errs = append(errs, value.err)
+	}
+	return fieldsHashMap, duplicates, errors.Join(errs...)
+}
+
+// rejectedValue is a field value that could not be parsed, see parseFieldsWithOffset.
+type rejectedValue struct {
+	offset int   // The index of the value (or of the field) in the clean entry.
+	err    error // Why the value was rejected.
+}
+
+// parseFieldsWithOffset parses all fields like parseFields and additionally returns the
+// field names as written in the entry, keyed by the lowercased names. A field whose value
+// (or name) cannot be parsed is left out, and its error is returned with the index of the
+// value in the clean entry as a rejectedValue; all other fields are still parsed. The
+// error is only non-nil if the body of the entry cannot be found.
+// Irregular separators between the fields are tolerated: a missing ',' between a value and
+// the next field (e.g., author = {X} title = {Y}) and repeated ',' (e.g., year = {2024},,)
+// are returned as ErrMissingComma and ErrExtraComma warnings without the key of the entry.
+// If positions is not nil, the index of each field name in the clean entry is stored in it.
+// If expressions is not nil, the values referencing macros (e.g., jan or acm # " Press") are
+// stored in it as written, see isMacroExpression.
+func parseFieldsWithOffset(cleanBibtexEntry string, macros map[string]string, positions map[string]int, expressions map[string]string) (map[string]string, map[string]string, []string, []error, []rejectedValue, error) {
 	fieldsHashMap := make(map[string]string)
+	rawFieldNames := make(map[string]string)
+	var duplicates []string
+	var separatorWarnings []error
+	var rejected []rejectedValue
 	// Get the inner field first.
-	// Example: @article{id, author={Thomas Jurczy},...}
-	// Here, the inner field is id, author={Thomas Jurczy},...
-	_, innerField, found := strings.Cut(cleanBibtexEntry, "{")
-	if !found {
-		return nil, &ErrParsingEntry{Message: fmt.Sprintf("Could not split on '{': %s", cleanBibtexEntry)}
+	innerField, bodyOffset, err := entryBody(cleanBibtexEntry)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
 	}
-	// Check if innerField is empty
-	innerField = strings.TrimSpace(innerField)
-	if len(innerField) == 0 {
-		return nil, &ErrEmptyString{Message: "The string is empty."}
-	}
-	// Verify trailing '}'
-	if innerField[len(innerField)-1] != '}' {
-		return nil, &ErrParsingEntry{Message: "The last char in fields list should be '}'."}
-	}
-	// Remove trailing '}'
-	innerField = innerField[:len(innerField)-1]
-	// Trying to find all valid fields via their field name indices
-	matches := regexFindFieldNames.FindAllStringIndex(innerField, -1)
-	// Storing field information in list
-	// Difficult and needs better documentation
-	lastIndex := 0
-	previousFieldName := ""
-	// Iterating over all matches
-	for _, match := range matches {
-		// Add previous text as value for the field
-		if match[0] > lastIndex {
-			if previousFieldName != "" {
-				// Adding value to field
-				// Clean field value
-				v := innerField[lastIndex:match[0]]
-				v = strings.TrimSpace(v)
-				// Create []rune slice
-				vrunes := []rune(v)
-				// Check that v is not empty
-				if len(vrunes) == 0 {
-					continue
-				}
-				// Check if last char is ',' and remove if this is the case
-				if vrunes[len(vrunes)-1] == ',' {
-					vrunes = vrunes[:len(vrunes)-1]
-					vrunes = []rune(strings.TrimSpace(string(vrunes)))
-				}
-				// Remove trailing and leading '{}' or '""'
-				if (vrunes[0] == '"' && vrunes[len(vrunes)-1] == '"') || (vrunes[0] == '{' && vrunes[len(vrunes)-1] == '}') {
-					vrunes = vrunes[1 : len(vrunes)-1]
-				} else {
-					return nil, &ErrParsingEntry{Message: fmt.Sprintf(`The first and last char in field value should either be {} or "": %s`, v)}
-				}
-				fieldsHashMap[previousFieldName] = string(vrunes)
+	// The field before the separator, "" for the key
+	previousField := ""
+	// Trying to find all valid fields via their field name indices. The next field is
+	// searched after the end of the last field value, as matches inside of a field
+	// value (e.g., {x = {y}}) are no fields.
+	for valueEnd := 0; valueEnd < len(innerField); {
+		nameStart, nameEnd, nextValueStart := findFieldName(innerField[valueEnd:])
+		if nameStart == -1 {
+			// Only a single trailing ',' is expected after the last value
+			if strings.Count(innerField[valueEnd:], ",") > 1 {
+				separatorWarnings = append(separatorWarnings, &ErrExtraComma{Field: previousField})
 			}
+			break
+		}
+		// A single ',' is expected between the key or the previous value and the field
+		if strings.Count(innerField[valueEnd:valueEnd+nameStart], ",") > 1 {
+			separatorWarnings = append(separatorWarnings, &ErrExtraComma{Field: previousField})
 		}
-		// Adding the field name as key to HashMap
 		// Clean field name
-		fieldName := innerField[match[0] : match[1]-1]
-		fieldName = strings.ReplaceAll(fieldName, "=", "")
-		fieldName = strings.TrimSpace(fieldName)
-		fieldName = strings.ToLower(fieldName)
-		if fieldName != "" {
-			fieldsHashMap[fieldName] = ""
-			previousFieldName = fieldName
-		}
-		lastIndex = match[1] - 1
-	}
-	// Add remaining value
-	if lastIndex < len(innerField) {
-		if previousFieldName != "" {
-			v := innerField[lastIndex:]
-			v = strings.TrimSpace(v)
-			vrunes := []rune(v)
-			if len(vrunes) > 0 {
-				if vrunes[len(vrunes)-1] == ',' {
-					vrunes = vrunes[:len(vrunes)-1]
-					vrunes = []rune(strings.TrimSpace(string(vrunes)))
-				}
-				if (vrunes[0] == '"' && vrunes[len(vrunes)-1] == '"') || (vrunes[0] == '{' && vrunes[len(vrunes)-1] == '}') {
-					vrunes = vrunes[1 : len(vrunes)-1]
-				} else {
-					return nil, &ErrParsingEntry{Message: fmt.Sprintf(`The first and last char in field value should either be {} or "": %s`, v)}
+		paddedFieldName := innerField[valueEnd+nameStart : valueEnd+nameEnd]
+		rawFieldName := strings.TrimSpace(paddedFieldName)
+		fieldName := strings.ToLower(rawFieldName)
+		nameOffset := bodyOffset + valueEnd + nameStart
+		position := nameOffset + len(paddedFieldName) - len(strings.TrimLeftFunc(paddedFieldName, unicode.IsSpace))
+		// The field value ends with the first ',' outside of braces and quotes
+		valueStart := valueEnd + nextValueStart
+		valueEnd = findValueEnd(innerField, valueStart)
+		if nextField := findMissingComma(innerField, valueStart, valueEnd); nextField != -1 {
+			// Parse the next field separately
+			valueEnd = nextField
+			nextFieldName := strings.ToLower(regexFieldStart.FindString(innerField[nextField:]))
+			separatorWarnings = append(separatorWarnings, &ErrMissingComma{Field: strings.TrimSpace(strings.TrimSuffix(nextFieldName, "="))})
+		}
+		// Clean field value
+		v := strings.TrimSpace(innerField[valueStart:valueEnd])
+		if fieldName == "" {
+			rejected = append(rejected, rejectedValue{offset: nameOffset, err: &ErrParsingEntry{Message: fmt.Sprintf("Could not find the field name before '=': %s", strings.TrimSpace(innerField[nameOffset-bodyOffset:valueEnd]))}})
+			continue
+		}
+		previousField = fieldName
+		// Remove trailing and leading '{}' or '""' or resolve macros
+		value := ""
+		if len(v) > 0 {
+			value, err = parseFieldValue(v, macros)
+			if err != nil {
+				var mismatchedErr *ErrMismatchedDelimiters
+				if errors.As(err, &mismatchedErr) {
+					mismatchedErr.Field = fieldName
 				}
-				fieldsHashMap[previousFieldName] = string(vrunes)
+				rejected = append(rejected, rejectedValue{offset: bodyOffset + valueStart, err: err})
+				continue
+			}
+		}
+		if _, ok := rawFieldNames[fieldName]; ok {
+			duplicates = append(duplicates, fieldName)
+		}
+		rawFieldNames[fieldName] = rawFieldName
+		fieldsHashMap[fieldName] = value
+		if positions != nil {
+			positions[fieldName] = position
+		}
+		if expressions != nil && len(v) > 0 && isMacroExpression(v) {
+			expressions[fieldName] = v
+		} else {
+			delete(expressions, fieldName)
+		}
+	}
+	return fieldsHashMap, rawFieldNames, duplicates, separatorWarnings, rejected, nil
+}
+
+// findMismatchedDelimiters returns an ErrMismatchedDelimiters for the first field value
+// without nested braces that is opened by '{' but closed by '"' (e.g., title = {value",),
+// together with the position of the value in the entry. The value has to be followed by
+// the next field or the end of the entry, so quotes inside of values are not mistaken for
+// delimiters. It returns nil if there is no such value.
+func findMismatchedDelimiters(entry string) (*ErrMismatchedDelimiters, int) {
+	match := regexMismatchedDelimiters.FindStringSubmatchIndex(entry)
+	if match == nil {
+		return nil, -1
+	}
+	return &ErrMismatchedDelimiters{
+		Field: strings.ToLower(entry[match[2]:match[3]]),
+		Open:  '{',
+		Close: '"',
+		Value: entry[match[4]:match[5]],
+	}, match[4]
+}
+
+// repairMissingBrace returns the raw entry with a '}' in a new line after its last
+// non-blank line if this '}' is the only missing brace of a brace-delimited entry (see
+// ParseOptions.RepairMissingBrace), and false with the unchanged entry otherwise.
+// Entries that swallowed the following entries of the file (see hasNestedEntry) are not
+// repaired, as their '}' is missing in the middle of the file instead of at its end.
+func repairMissingBrace(rawEntry string) (string, bool) {
+	if checkBraceBalance(cleanRawEntry(rawEntry)) == nil || hasNestedEntry(rawEntry) {
+		return rawEntry, false
+	}
+	// The new line keeps the '}' out of a % comment in the last line
+	repaired := strings.TrimRight(rawEntry, " \t\r\n") + "\n}"
+	if checkBraceBalance(cleanRawEntry(repaired)) != nil {
+		return rawEntry, false
+	}
+	return repaired, true
+}
+
+// hasNestedEntry reports whether another entry (e.g., @article{) starts at the beginning
+// of a line inside of the raw entry, i.e., the scanner read the following entries of the
+// file into an entry that misses its closing brace.
+func hasNestedEntry(rawEntry string) bool {
+	lineStart := false
+	for i := 0; i < len(rawEntry); i++ {
+		switch char := rawEntry[i]; {
+		case char == '\n':
+			lineStart = true
+		case char == ' ' || char == '\t' || char == '\r':
+		case char == '@' && lineStart && regexEntryType.MatchString(rawEntry[i:]):
+			return true
+		default:
+			lineStart = false
+		}
+	}
+	return false
+}
+
+// checkBraceBalance checks that every '{' of the entry is closed by a '}' and that no '}'
+// appears without a matching '{'. Escaped braces like \{ are skipped.
+// It returns an ErrUnbalancedBraces with the position of the first unmatched '}' or,
+// if all braces are matched, of the innermost '{' that is never closed.
+func checkBraceBalance(entry string) error {
+	var openings []int
+	for i := 0; i < len(entry); i++ {
+		switch entry[i] {
+		case '\\':
+			// Skip the escaped char
+			i++
+		case '{':
+			openings = append(openings, i)
+		case '}':
+			if len(openings) == 0 {
+				return &ErrUnbalancedBraces{Position: i}
+			}
+			openings = openings[:len(openings)-1]
+		}
+	}
+	if len(openings) > 0 {
+		return &ErrUnbalancedBraces{Position: openings[len(openings)-1], Unclosed: true}
+	}
+	return nil
+}
+
+// findClosingBrace returns the index of the first '}' that closes the
+// brace group opened before the string, or -1 if there is no such '}'.
+// Escaped braces like \{ and \} are skipped.
+func findClosingBrace(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			// Skip the escaped char
+			i++
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
 			}
+			depth--
 		}
 	}
-	return fieldsHashMap, nil
+	return -1
+}
+
+// findClosingParen returns the index of the first ')' that is neither enclosed in
+// braces nor in quotes, i.e., the end of an entry body opened by '(', or -1 if there is no such ')'.
+// Escaped chars like \) are skipped.
+func findClosingParen(s string) int {
+	depth := 0
+	inQuotes := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			// Skip the escaped char
+			i++
+		case s[i] == '{':
+			depth++
+		case s[i] == '}':
+			depth--
+		case s[i] == '"' && depth == 0:
+			inQuotes = !inQuotes
+		case s[i] == ')' && depth == 0 && !inQuotes:
+			return i
+		}
+	}
+	return -1
+}
+
+// findValueEnd returns the index of the first ',' after start that is neither
+// enclosed in braces nor in quotes, i.e., the end of a field value.
+// Nested brace groups like {A study of {Go}} are skipped as a whole.
+// Escaped chars like \{, \}, or \" neither open nor close braces and quotes.
+// If there is no such ',', the length of the string is returned.
+func findValueEnd(s string, start int) int {
+	depth := 0
+	inQuotes := false
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			// Skip the escaped char
+			i++
+		case s[i] == '{':
+			depth++
+		case s[i] == '}':
+			depth--
+		case s[i] == '"' && depth == 0:
+			inQuotes = !inQuotes
+		case s[i] == ',' && depth <= 0 && !inQuotes:
+			return i
+		}
+	}
+	return len(s)
+}
+
+// findMissingComma returns the index of the first field (e.g., title =) between start and end
+// that is not enclosed in braces or quotes and follows a value without a separating ','
+// (e.g., {X} title = {Y}), or -1 if there is none.
+func findMissingComma(s string, start, end int) int {
+	depth := 0
+	inQuotes := false
+	for i := start; i < end; i++ {
+		switch {
+		case s[i] == '\\':
+			// Skip the escaped char
+			i++
+		case s[i] == '{':
+			depth++
+		case s[i] == '}':
+			depth--
+		case s[i] == '"' && depth == 0:
+			inQuotes = !inQuotes
+		case depth == 0 && !inQuotes && i > start && strings.IndexByte(" }\"", s[i-1]) != -1 && regexFieldStart.MatchString(s[i:end]):
+			return i
+		}
+	}
+	return -1
+}
+
+// findFieldName finds the first field in s and returns the start and end of its name
+// (including surrounding white spaces) and the start of its value, or -1 for all three if
+// there is no field. The field name consists of letters, digits, and _:.- (but does not
+// start with a digit, e.g., date-added or bdsk-url-1) and is followed by '='. The value
+// is either delimited by {} or "" or is a bare number or @string macro name (e.g.,
+// year = 2024 or publisher = acm), which has to be followed by ',', '#', the end of s, or
+// the next field if the ',' is missing (e.g., year = 2024 title = {...}).
+// findFieldName runs in linear time, as every '=' ends the field name before it and the
+// bare value after it is scanned at most up to the next '='.
+func findFieldName(s string) (int, int, int) {
+	nameStart := -1
+	for i := 0; i < len(s); i++ {
+		switch {
+		case isFieldNameChar(s[i]) || isFieldSpace(s[i]):
+			if nameStart == -1 {
+				nameStart = i
+			}
+		case s[i] == '=' && nameStart != -1:
+			if first := skipFieldSpaces(s, nameStart); first < i && !isFieldNameStart(s[first]) {
+				nameStart = -1
+				continue
+			}
+			if valueStart := skipFieldSpaces(s, i+1); isFieldValueStart(s[valueStart:]) {
+				return nameStart, i, valueStart
+			}
+			nameStart = -1
+		default:
+			nameStart = -1
+		}
+	}
+	return -1, -1, -1
+}
+
+// isFieldValueStart reports whether s starts with a field value, see findFieldName.
+func isFieldValueStart(s string) bool {
+	if s != "" && (s[0] == '{' || s[0] == '"') {
+		return true
+	}
+	// Bare values are followed by ',', '#', the end, or the name of the next field
+	end := 0
+	for end < len(s) && isBareValueChar(s[end]) {
+		end++
+	}
+	if end == 0 {
+		return false
+	}
+	next := skipFieldSpaces(s, end)
+	if next == len(s) || s[next] == ',' || s[next] == '#' {
+		return true
+	}
+	// The next field name is either separated by white spaces or consists of the trailing
+	// letters of the bare value (e.g., year = 2024title = {...})
+	if s[next] == '=' {
+		return end > 1 && isASCIILetter(s[end-1])
+	}
+	if !isFieldNameStart(s[next]) {
+		return false
+	}
+	nameEnd := next
+	for nameEnd < len(s) && isFieldNameChar(s[nameEnd]) {
+		nameEnd++
+	}
+	equals := skipFieldSpaces(s, nameEnd)
+	return nameEnd > next && equals < len(s) && s[equals] == '='
+}
+
+// isFieldSpace reports whether c is an ASCII white space around field names and values.
+func isFieldSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+// isFieldNameChar reports whether c can be part of a field name, see findFieldName.
+func isFieldNameChar(c byte) bool {
+	return isASCIILetter(c) || (c >= '0' && c <= '9') || strings.IndexByte("_:.-", c) != -1
+}
+
+// isFieldNameStart reports whether a field name can start with c, i.e., c is no digit.
+func isFieldNameStart(c byte) bool {
+	return isFieldNameChar(c) && (c < '0' || c > '9')
+}
+
+// isBareValueChar reports whether c can be part of a bare number or @string macro name.
+func isBareValueChar(c byte) bool {
+	return isASCIILetter(c) || (c >= '0' && c <= '9') || strings.IndexByte("_.:+-", c) != -1
+}
+
+// skipFieldSpaces returns the index of the first char in s at or after start that is no
+// white space (see isFieldSpace), or len(s).
+func skipFieldSpaces(s string, start int) int {
+	for start < len(s) && isFieldSpace(s[start]) {
+		start++
+	}
+	return start
+}
+
+// parseFieldValue parses a single field value without its trailing ','.
+// The value is either delimited by {} or "", a bare number, a @string macro name,
+// or a concatenation of those joined by '#' (e.g., pub # " Press").
+// Macro names are resolved against the given macros and the predefined month macros.
+func parseFieldValue(v string, macros map[string]string) (string, error) {
+	// Most values are no concatenations, which can be returned without copying
+	if !strings.Contains(v, "#") {
+		return parseFieldValuePart(v, v, macros)
+	}
+	var builder strings.Builder
+	for _, part := range splitConcatenation(v) {
+		value, err := parseFieldValuePart(part, v, macros)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(value)
+	}
+	return builder.String(), nil
+}
+
+// parseFieldValuePart returns a single part of the field value v (see parseFieldValue),
+// which is either delimited by {} or "", a bare number, or a macro name.
+func parseFieldValuePart(part, v string, macros map[string]string) (string, error) {
+	part = strings.TrimSpace(part)
+	switch {
+	case strings.HasPrefix(part, "{") || strings.HasPrefix(part, `"`):
+		// Remove trailing and leading '{}' or '""'
+		return stripOuterDelimiters(part)
+	case regexBareNumber.MatchString(part):
+		return part, nil
+	case regexMacroName.MatchString(part):
+		value, ok := lookupMacro(part, macros)
+		if !ok {
+			return "", &ErrUndefinedMacro{Name: part}
+		}
+		return value, nil
+	default:
+		return "", &ErrParsingEntry{Message: fmt.Sprintf(`The first and last char in field value should either be {} or "": %s`, v)}
+	}
+}
+
+// stripOuterDelimiters removes the outermost matching pair of '{}' or '""' from a field value.
+// Inner brace groups are left untouched, so {{Einstein}} becomes {Einstein}.
+// An error is returned if the value is not delimited or if the outer braces belong
+// to independent groups like {A} {B}. A value that is opened by one kind of delimiter and
+// closed by the other (e.g., {value") is an ErrMismatchedDelimiters.
+func stripOuterDelimiters(v string) (string, error) {
+	// A single '"' or '{' is both the first and the last char, but no pair of delimiters
+	if len(v) < 2 {
+		return "", &ErrParsingEntry{Message: fmt.Sprintf(`The first and last char in field value should either be {} or "": %s`, v)}
+	}
+	if (v[0] == '{' && v[len(v)-1] == '"' && !isEscaped(v, len(v)-1)) || (v[0] == '"' && v[len(v)-1] == '}') {
+		return "", &ErrMismatchedDelimiters{Open: rune(v[0]), Close: rune(v[len(v)-1]), Value: v}
+	}
+	if v[0] == '"' && v[len(v)-1] == '"' && !isEscaped(v, len(v)-1) {
+		return v[1 : len(v)-1], nil
+	}
+	if v[0] == '{' && v[len(v)-1] == '}' {
+		// The brace opened by the first char must be closed by the last char
+		if findClosingBrace(v[1:]) != len(v)-2 {
+			return "", &ErrParsingEntry{Message: fmt.Sprintf("The outer braces should enclose the whole field value: %s", v)}
+		}
+		return v[1 : len(v)-1], nil
+	}
+	return "", &ErrParsingEntry{Message: fmt.Sprintf(`The first and last char in field value should either be {} or "": %s`, v)}
+}
+
+// splitConcatenation splits a field value on all '#' that are neither enclosed
+// in braces nor in quotes. Escaped chars like \# or \" are skipped.
+func splitConcatenation(v string) []string {
+	var parts []string
+	depth := 0
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(v); i++ {
+		switch char := v[i]; {
+		case char == '\\':
+			// Skip the escaped char
+			i++
+		case char == '{':
+			depth++
+		case char == '}':
+			depth--
+		case char == '"' && depth == 0:
+			inQuotes = !inQuotes
+		case char == '#' && depth == 0 && !inQuotes:
+			parts = append(parts, v[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, v[start:])
+}
+
+// isMacroExpression reports whether the field value v references a macro, i.e., it is a
+// macro name (e.g., jan) or a concatenation (e.g., acm # " Press").
+func isMacroExpression(v string) bool {
+	return regexMacroName.MatchString(v) || len(splitConcatenation(v)) > 1
+}
+
+// isEscaped checks if the char at the given index is escaped by an odd number of backslashes (e.g., \").
+func isEscaped(s string, index int) bool {
+	backslashes := 0
+	for i := index - 1; i >= 0 && s[i] == '\\'; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+// lookupMacro resolves a macro name against the given macros and the predefined
+// month macros. Macro names are case-insensitive.
+func lookupMacro(name string, macros map[string]string) (string, bool) {
+	name = strings.ToLower(name)
+	if value, ok := macros[name]; ok {
+		return value, true
+	}
+	value, ok := defaultMacros[name]
+	return value, ok
 }
 
 // parseID searches for a BibTeX ID in a clean (!) BibTeX entry.
 // For cleaning a BibTeX entry, see cleanRawEntry().
+// It returns an empty ID without an error if no key was written at all (e.g., @misc{, title = {A}}),
+// which is reported as an ErrMissingKey by Validate.
 func parseID(cleanBibtexEntry string) (string, error) {
 	// Get the inner field first.
-	// Example: @article{id, author={Thomas Jurczy},...}
-	// Here, the inner field is id, author={Thomas Jurczy},...
-	_, innerField, found := strings.Cut(cleanBibtexEntry, "{")
-	if !found {
-		return "", &ErrParsingEntry{Message: fmt.Sprintf("Could not split on '{': %s", cleanBibtexEntry)}
-	}
-	// Check if innerField is empty
-	innerField = strings.TrimSpace(innerField)
-	if len(innerField) == 0 {
-		return "", &ErrEmptyString{Message: "The string is empty."}
-	}
-	// Verify trailing '}'
-	if innerField[len(innerField)-1] != '}' {
-		return "", &ErrParsingEntry{Message: "The last char in fields list should be '}'."}
-	}
-	// Remove trailing '}'
-	innerField = innerField[:len(innerField)-1]
-	id := regexFindID.FindString(innerField)
-	// Remove potential ',' in the beginning
-	if len(id) > 0 && id[0] == ',' {
-		id = id[1:]
+	innerField, _, err := entryBody(cleanBibtexEntry)
+	if err != nil {
+		return "", err
 	}
-	// Remove potential ',' in the end
-	if len(id) > 0 && id[len(id)-1] == ',' {
-		id = id[:len(id)-1]
+	// The ID is the first segment between ',' outside of field values that is
+	// no field, e.g., "id" in "author={A, B}, id, title={C}"
+	// The first segment that is no field, but contains invalid chars (e.g., "my key")
+	var invalidID string
+	for start := 0; start < len(innerField); {
+		end := findValueEnd(innerField, start)
+		id := strings.TrimSpace(innerField[start:end])
+		if regexFindID.MatchString(id) {
+			return id, nil
+		}
+		if invalidID == "" && id != "" && !strings.Contains(id, "=") {
+			invalidID = id
+		}
+		start = end + 1
 	}
-	idTrimmed := strings.TrimSpace(id)
-	if idTrimmed == "" {
-		return "", &ErrParsingEntry{Message: "Could not find ID in BibTeX entry."}
+	if invalidID != "" {
+		return "", validateKey(invalidID)
 	}
-	return idTrimmed, nil
+	return "", nil
 }
 
-// safeGet retrieves the element at the specified index from the slice.
-// It returns the element and a boolean indicating whether the access was successful.
-func safeGet[T any](slice []T, index int) (T, bool) {
-	var zeroValue T
-	if index >= 0 && index < len(slice) {
-		return slice[index], true
+// validateKey checks if the key only contains chars that are allowed in BibTeX keys
+// (letters, digits, and .-/:_). It returns an ErrInvalidKey with the first invalid char otherwise.
+func validateKey(key string) error {
+	for _, char := range key {
+		if !regexFindID.MatchString(string(char)) {
+			return &ErrInvalidKey{Key: key, BadChar: char}
+		}
 	}
-	return zeroValue, false
+	return nil
 }
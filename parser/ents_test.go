@@ -2,11 +2,17 @@
 package parser
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCleanRawEntry(t *testing.T) {
@@ -53,6 +59,33 @@ func TestCleanRawEntry(t *testing.T) {
 	if result3 != expected3 {
 		t.Errorf("Expected '%s', but got '%s'", expected3, result3)
 	}
+	// Case 4: The char before the % is kept
+	result4 := cleanRawEntry("foo%bar")
+	expected4 := "foo"
+	if result4 != expected4 {
+		t.Errorf("Expected '%s', but got '%s'", expected4, result4)
+	}
+
+	// Case 5: % inside of field values is no comment (e.g., percent-encoded URLs)
+	testCase5 := `@misc{doe2021, % comment
+	url = {http://x?a=1%20b}, % comment
+	note = "50% off", % comment
+}`
+	expected5 := `@misc{doe2021,url = {http://x?a=1%20b},note = "50% off",}`
+	if result5 := cleanRawEntry(testCase5); result5 != expected5 {
+		t.Errorf("Expected '%s', but got '%s'", expected5, result5)
+	}
+	entry5, _ := ParseNewEntry(testCase5)
+	if url := entry5.Fields["url"]; url != "http://x?a=1%20b" {
+		t.Errorf("Expected '%s', but got '%s'", "http://x?a=1%20b", url)
+	}
+
+	// Case 6: Braced values of entries delimited by ()
+	testCase6 := "@misc(doe2021, % comment\n  url = {http://x/%7Edoe})"
+	expected6 := "@misc(doe2021,url = {http://x/%7Edoe})"
+	if result6 := cleanRawEntry(testCase6); result6 != expected6 {
+		t.Errorf("Expected '%s', but got '%s'", expected6, result6)
+	}
 }
 
 func TestParseEntryType(t *testing.T) {
@@ -102,6 +135,93 @@ func TestParseEntryType(t *testing.T) {
 	if err4 == nil || expected6.Error() != err4.Error() {
 		t.Errorf("Expected '%#v', but got '%#v'", expected6, err4)
 	}
+	// Case 7: Entry types are lowercased, the original casing is kept by parseRawEntryType
+	testCase7 := `@ARTICLE{id1234,author={Jurczyk, Thomas},date={20.12.2023}}`
+	expected7a := `article`
+	result7a, _ := parseEntryType(testCase7)
+	if expected7a != result7a {
+		t.Errorf("Expected '%s', but got '%s'", expected7a, result7a)
+	}
+	expected7b := `ARTICLE`
+	result7b, _ := parseRawEntryType(testCase7)
+	if expected7b != result7b {
+		t.Errorf("Expected '%s', but got '%s'", expected7b, result7b)
+	}
+	entry7, _ := ParseNewEntry(testCase7)
+	if entry7.EntryType != expected7a || entry7.RawEntryType != expected7b {
+		t.Errorf("Expected '%s' and '%s', but got '%s' and '%s'", expected7a, expected7b, entry7.EntryType, entry7.RawEntryType)
+	}
+}
+
+func TestCleanRawEntryWithMap(t *testing.T) {
+	// Case 1: Offsets are mapped to the lines of the raw entry
+	input := "@article{id,\n  author = {Thomas\n    Jurczyk}, % comment\n\n  year = {2024}\n}"
+	expected := "@article{id,author = {Thomas Jurczyk},year = {2024}}"
+	result, lines := cleanRawEntryWithMap(input)
+	if expected != result {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, result)
+	}
+	if len(lines) != len(result) {
+		t.Fatalf("Expected '%#v', but got '%#v'", len(result), len(lines))
+	}
+	for _, c := range []struct {
+		offset int
+		line   int
+	}{{0, 1}, {strings.Index(result, "author"), 2}, {strings.Index(result, "Jurczyk"), 3}, {strings.Index(result, "year"), 5}, {len(result) - 1, 6}} {
+		if line := lines[c.offset]; line != c.line {
+			t.Errorf("Expected offset '%#v' on line '%#v', but got '%#v'", c.offset, c.line, line)
+		}
+	}
+	// Case 2: Same result as cleanRawEntry
+	if cleanRawEntry(input) != result {
+		t.Errorf("Expected '%#v', but got '%#v'", result, cleanRawEntry(input))
+	}
+}
+
+func TestCheckBraceBalance(t *testing.T) {
+	// Case 1: Balanced braces, also with escaped braces
+	for _, entry := range []string{`@article{id, title = {A {B}}}`, `@misc{id, title = {a \{ b}}`, `no braces`} {
+		if err := checkBraceBalance(entry); err != nil {
+			t.Errorf("Expected no error for '%s', but got '%#v'", entry, err)
+		}
+	}
+	// Case 2: Unclosed brace
+	expected2 := &ErrUnbalancedBraces{Position: 21, Unclosed: true}
+	err2 := checkBraceBalance(`@article{id, title = {unclosed`)
+	if !reflect.DeepEqual(expected2, err2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, err2)
+	}
+	// Case 3: Unmatched closing brace
+	expected3 := &ErrUnbalancedBraces{Position: 24}
+	err3 := checkBraceBalance(`@article{id, title = {}}}`)
+	if !reflect.DeepEqual(expected3, err3) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, err3)
+	}
+	// Case 4: The line is added when parsing a BibTeX file
+	bibtexFile, _ := ParseNewBibTeXFile(strings.NewReader("@misc{ok, title = {OK}}\n@article{id,\n  title = {unclosed,\n  year = 2020\n"))
+	expected4 := []error{&ErrUnbalancedBraces{Position: 20, Line: 3, Unclosed: true}}
+	if !reflect.DeepEqual(expected4, bibtexFile.Errors) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected4, bibtexFile.Errors)
+	}
+	expected4Message := "Error parsing a BibTeX entry (line 3): unbalanced braces, '{' at position 20 is never closed"
+	if len(bibtexFile.Errors) == 1 && expected4Message != bibtexFile.Errors[0].Error() {
+		t.Errorf("Expected '%s', but got '%s'", expected4Message, bibtexFile.Errors[0].Error())
+	}
+}
+
+func TestWithLine(t *testing.T) {
+	// Case 1: Line is added to ErrParsingEntry
+	expected1 := "Error parsing a BibTeX entry (line 12): Something is wrong."
+	err1 := withLine(&ErrParsingEntry{Message: "Something is wrong."}, 12)
+	if expected1 != err1.Error() {
+		t.Errorf("Expected '%s', but got '%s'", expected1, err1.Error())
+	}
+	// Case 2: Other errors are not changed
+	expected2 := &ErrEmptyString{Message: "The string is empty."}
+	err2 := withLine(expected2, 12)
+	if expected2.Error() != err2.Error() {
+		t.Errorf("Expected '%s', but got '%s'", expected2.Error(), err2.Error())
+	}
 }
 
 func TestParseFields(t *testing.T) {
@@ -129,7 +249,7 @@ func TestParseFields(t *testing.T) {
 	// Sort list for comparison
 	sort.Strings(expected1)
 
-	fields, err := parseFields(entry1)
+	fields, _, err := parseFields(entry1, nil)
 	// Collect field names
 	fieldNameList := make([]string, 0, 8)
 	for k := range fields {
@@ -147,7 +267,7 @@ func TestParseFields(t *testing.T) {
 	entry2 := `@book{schmidt2024,author = {Schmidt, Anna and Müller, Bernd and {O'Connor}, Claire and García, Diego},language = "Deutsch"}`
 	expected2 := map[string]string{"author": "Schmidt, Anna and Müller, Bernd and {O'Connor}, Claire and García, Diego", "language": "Deutsch"}
 
-	fields2, _ := parseFields(entry2)
+	fields2, _, _ := parseFields(entry2, nil)
 
 	if !reflect.DeepEqual(expected2, fields2) {
 		t.Errorf("Expected '%#v', but got '%#v'", expected2, fields2)
@@ -166,12 +286,262 @@ func TestParseFields(t *testing.T) {
 `
 	expected3 := map[string]string{"author": "Max Mustermann", "title": "Einführung in die Datenwissenschaft", "journal": "Journal für Informatik", "year": "2024", "volume": "42", "number": "3", "pages": "123--145"}
 
-	fields3, _ := parseFields(entry3)
+	fields3, _, _ := parseFields(entry3, nil)
 
 	if !reflect.DeepEqual(expected3, fields3) {
 		t.Errorf("Expected '%#v', but got '%#v'", expected3, fields3)
 	}
 
+	// Case 4: Bare numbers, macros and concatenation
+	entry4 := `@book{acm2024,publisher = pub # " Press",year = 2024,month = jan, note = {Let x = 5, then}}`
+	expected4 := map[string]string{"publisher": "ACM Press", "year": "2024", "month": "January", "note": "Let x = 5, then"}
+
+	fields4, _, err4 := parseFields(entry4, map[string]string{"pub": "ACM"})
+
+	if !reflect.DeepEqual(expected4, fields4) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected4, fields4)
+		t.Errorf("Error: %v", err4)
+	}
+
+	// Case 5: Nested braces for protected capitalization
+	entry5 := `@article{nasa2024,
+  title   = {A study of {Go} at {NASA}},
+  journal = {{NASA} Technical {Reports}},
+  note    = {Uses {x = {y}}, see {Appendix}}
+}`
+	expected5 := map[string]string{"title": "A study of {Go} at {NASA}", "journal": "{NASA} Technical {Reports}", "note": "Uses {x = {y}}, see {Appendix}"}
+
+	fields5, _, err5 := parseFields(entry5, nil)
+
+	if !reflect.DeepEqual(expected5, fields5) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected5, fields5)
+		t.Errorf("Error: %v", err5)
+	}
+
+	// Case 6: Nested closing brace must not be mistaken for the entry's closing brace
+	entry6 := `@article{nasa2024, title = {A study of {NASA}}`
+	expected6 := &ErrParsingEntry{Message: fmt.Sprintf("Could not find the closing '}' of the entry: %s", entry6)}
+
+	_, _, err6 := parseFields(entry6, nil)
+
+	if err6 == nil || expected6.Error() != err6.Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected6, err6)
+	}
+
+	// Case 7: Undefined macro
+	entry7 := `@book{acm2024,publisher = pub,year = 2024}`
+	expected7 := &ErrUndefinedMacro{Name: "pub"}
+
+	_, _, err7 := parseFields(entry7, nil)
+
+	if err7 == nil || expected7.Error() != err7.Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected7, err7)
+	}
+
+	// Case 8: Escaped quotes in a quote-delimited value
+	entry8 := `@misc{id,title = "He said \"hi\", then left",year = 2024}`
+	expected8 := map[string]string{"title": `He said \"hi\", then left`, "year": "2024"}
+	result8, _, err8 := parseFields(entry8, nil)
+	if err8 != nil || !reflect.DeepEqual(expected8, result8) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", expected8, result8, err8)
+	}
+
+	// Case 9: Escaped braces in a brace-delimited value
+	entry9 := `@misc{id,title = {a \{ b, c},note = {x \} y}}`
+	expected9 := map[string]string{"title": `a \{ b, c`, "note": `x \} y`}
+	result9, _, err9 := parseFields(entry9, nil)
+	if err9 != nil || !reflect.DeepEqual(expected9, result9) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", expected9, result9, err9)
+	}
+
+	// Case 10: A quote-delimited value must not end with an escaped quote
+	entry10 := `@misc{id,title = "unterminated\"}`
+	expected10 := &ErrParsingEntry{Message: `The first and last char in field value should either be {} or "": "unterminated\"`}
+	_, _, err10 := parseFields(entry10, nil)
+	if err10 == nil || expected10.Error() != err10.Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected10, err10)
+	}
+
+	// Case 11: Repeated fields are reported, the last value is kept
+	entry11 := `@misc{id, year = {2023}, title = {A}, Year = {2024}, year = 2025}`
+	expected11 := map[string]string{"year": "2025", "title": "A"}
+	expectedDuplicates11 := []string{"year", "year"}
+	result11, duplicates11, err11 := parseFields(entry11, nil)
+	if err11 != nil || !reflect.DeepEqual(expected11, result11) || !reflect.DeepEqual(expectedDuplicates11, duplicates11) {
+		t.Errorf("Expected '%#v' and '%#v', but got '%#v' and '%#v' (%v)", expected11, expectedDuplicates11, result11, duplicates11, err11)
+	}
+}
+
+func TestStripOuterDelimiters(t *testing.T) {
+	// Case 1: Only the outermost pair is removed
+	cases := map[string]string{
+		`{{Einstein}}`:            `{Einstein}`,
+		`{The {LaTeX} companion}`: `The {LaTeX} companion`,
+		`"The {LaTeX} companion"`: `The {LaTeX} companion`,
+		`{}`:                      ``,
+		`""`:                      ``,
+		`{{A} and {B}}`:           `{A} and {B}`,
+	}
+	for input, expected := range cases {
+		result, err := stripOuterDelimiters(input)
+		if err != nil || expected != result {
+			t.Errorf("Expected '%s', but got '%s' (%v)", expected, result, err)
+		}
+	}
+
+	// Case 2: Two independent groups
+	expected2 := &ErrParsingEntry{Message: "The outer braces should enclose the whole field value: {A} {B}"}
+	_, err2 := stripOuterDelimiters(`{A} {B}`)
+	if err2 == nil || expected2.Error() != err2.Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, err2)
+	}
+
+	// Case 3: Not delimited
+	expected3 := &ErrParsingEntry{Message: `The first and last char in field value should either be {} or "": Einstein}`}
+	_, err3 := stripOuterDelimiters(`Einstein}`)
+	if err3 == nil || expected3.Error() != err3.Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, err3)
+	}
+
+	// Case 4: A single delimiter char is no pair of delimiters
+	for _, input := range []string{`"`, `{`, `}`, ``} {
+		expected4 := &ErrParsingEntry{Message: `The first and last char in field value should either be {} or "": ` + input}
+		if result, err4 := stripOuterDelimiters(input); err4 == nil || expected4.Error() != err4.Error() {
+			t.Errorf("Expected '%#v', but got '%#v' (%v)", expected4, result, err4)
+		}
+	}
+
+	// Case 5: Empty values in entries, also with a trailing comma
+	for _, rawEntry := range []string{`@misc{k, title = {}}`, `@misc{k, title = ""}`, `@misc{k, title = "",}`, `@misc{k, title = {}, year = 2020}`} {
+		entry, err5 := ParseNewEntry(rawEntry)
+		if value, ok := entry.Fields["title"]; err5 != nil || !ok || value != "" {
+			t.Errorf("Expected an empty title, but got '%#v' (%v)", entry.Fields, err5)
+		}
+	}
+
+	// Case 6: A single quote as value
+	if _, err6 := ParseNewEntry(`@misc{k, title = "}`); err6 == nil {
+		t.Errorf("Expected an error for '%s'", `"`)
+	}
+}
+
+func TestFindFieldName(t *testing.T) {
+	// Case 1: Field names, followed by delimited and bare values or the next field
+	cases := []struct {
+		s        string
+		expected [3]int
+	}{
+		{"key, title = {x}", [3]int{4, 11, 13}},
+		{"key, year = 2024, x = {y}", [3]int{4, 10, 12}},
+		{"key, year = 2024title = {y}", [3]int{4, 10, 12}},
+		{"key, year = 2024 title = {y}", [3]int{4, 10, 12}},
+		{"key, pub = acm # \" Press\"", [3]int{4, 9, 11}},
+		{"key, x = ab = {y}", [3]int{4, 7, 9}},
+		{"key, x = = {y}", [3]int{8, 9, 11}},
+		{"key, x = 20 24, y = {z}", [3]int{15, 18, 20}},
+		{"key, x = a b", [3]int{-1, -1, -1}},
+		{"key, 2024={x}", [3]int{-1, -1, -1}},
+		{"key, date-added = {x}", [3]int{4, 16, 18}},
+		{"key, year = 2024 bdsk-url-1 = {y}", [3]int{4, 10, 12}},
+		{"", [3]int{-1, -1, -1}},
+	}
+	for _, c := range cases {
+		nameStart, nameEnd, valueStart := findFieldName(c.s)
+		if got := [3]int{nameStart, nameEnd, valueStart}; got != c.expected {
+			t.Errorf("Expected '%#v', but got '%#v' for '%s'", c.expected, got, c.s)
+		}
+	}
+}
+
+func TestParseFieldNames(t *testing.T) {
+	// Case 1: Field names with digits and _:.- are kept whole
+	cases := []struct {
+		entry    string
+		expected map[string]string
+	}{
+		{`@misc{k, date-added = {2024-01-01}}`, map[string]string{"date-added": "2024-01-01"}},
+		{`@book{k, isbn13 = {978-3-16-148410-0}}`, map[string]string{"isbn13": "978-3-16-148410-0"}},
+		{`@misc{k, bdsk-url-1 = {https://example.com}}`, map[string]string{"bdsk-url-1": "https://example.com"}},
+		{`@misc{k, my_field = {x}, title = {T}}`, map[string]string{"my_field": "x", "title": "T"}},
+		{`@misc{k, dc:title = {x}, a.b = 1}`, map[string]string{"dc:title": "x", "a.b": "1"}},
+		{`@misc{k, year = 2024 date-added = {x}}`, map[string]string{"year": "2024", "date-added": "x"}},
+	}
+	for _, c := range cases {
+		entry, err := ParseNewEntry(c.entry)
+		if err != nil {
+			t.Errorf("Expected no error, but got '%v' for '%s'", err, c.entry)
+			continue
+		}
+		if !reflect.DeepEqual(c.expected, entry.Fields) {
+			t.Errorf("Expected '%#v', but got '%#v'", c.expected, entry.Fields)
+		}
+	}
+
+	// Case 2: A missing field name is a parsing error instead of dropping the field
+	_, err2 := ParseNewEntry(`@misc{k, title = {T}, = {x}}`)
+	expected2 := &ErrParsingEntry{Message: "Could not find the field name before '=': = {x}"}
+	if err2 == nil || expected2.Error() != err2.Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, err2)
+	}
+}
+
+func TestParseMathValues(t *testing.T) {
+	// Case 1: Math and nested braces are captured whole, including commas, '=', '%', and
+	// escaped braces inside of the value
+	cases := map[string]string{
+		`@misc{k, note = {See {e.g., $x^2$}}, title = {T}}`:                           `See {e.g., $x^2$}`,
+		`@misc{k, note = "Quote {e.g., $x^2$} and $y$", title = {T}}`:                 `Quote {e.g., $x^2$} and $y$`,
+		`@misc{k, note = {We show $a_{i,j} = b$, title = {c}}, title = {T}}`:          `We show $a_{i,j} = b$, title = {c}`,
+		`@misc{k, note = {Costs $5\%$ of $\mathcal{O}(n^2)$}, title = {T}}`:           `Costs $5\%$ of $\mathcal{O}(n^2)$`,
+		`@misc{k, note = {Sets $\{x \mid x > 0\}$ and $\}$}, title = {T}}`:            `Sets $\{x \mid x > 0\}$ and $\}$`,
+		"@misc{k, note = {$f(x) = \\frac{1}{2}$ % no comment\n  $\\#$}, title = {T}}": `$f(x) = \frac{1}{2}$ % no comment $\#$`,
+		`@misc{k, note = {$a # b$} # { and } # "$c$", title = {T}}`:                   `$a # b$ and $c$`,
+	}
+	for rawEntry, expected := range cases {
+		entry, err := ParseNewEntry(rawEntry)
+		if err != nil || entry.Fields["note"] != expected || entry.Fields["title"] != "T" {
+			t.Errorf("Expected '%s', but got '%#v' (%v)", expected, entry, err)
+			continue
+		}
+		// Case 2: The math is kept when the entry is formatted and parsed again
+		reparsed, err := ParseNewEntry(entry.String())
+		if err != nil || reparsed.Fields["note"] != expected {
+			t.Errorf("Expected '%s', but got '%#v' (%v)", expected, reparsed, err)
+		}
+	}
+}
+
+func TestParseMismatchedDelimiters(t *testing.T) {
+	// Case 1: Values opened by one delimiter and closed by the other
+	cases := map[string]*ErrMismatchedDelimiters{
+		`@misc{k, title = {value", year = 2020}`: {Field: "title", Open: '{', Close: '"', Value: `{value"`},
+		`@misc{k, year = 2020, Title = {value"}`: {Field: "title", Open: '{', Close: '"', Value: `{value"`},
+		`@misc{k, title = "value {x}}`:           {Field: "title", Open: '"', Close: '}', Value: `"value {x}`},
+	}
+	for rawEntry, expected := range cases {
+		_, err := ParseNewEntry(rawEntry)
+		var mismatched *ErrMismatchedDelimiters
+		if !errors.As(err, &mismatched) || !reflect.DeepEqual(expected, mismatched) {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, err)
+		}
+	}
+
+	// Case 2: The message contains the field and the line in the BibTeX file
+	bibtexFile, _ := ParseString("@misc{a, title = {A}}\n\n@misc{b,\n  year = 2020,\n  title = {value\",\n  note = {N}}")
+	expected2 := `Error parsing a BibTeX entry (line 5): value of field 'title' starts with '{', but ends with '"': {value"`
+	if len(bibtexFile.Errors) != 1 || bibtexFile.Errors[0].Error() != expected2 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, bibtexFile.Errors)
+	}
+
+	// Case 3: Quotes inside of values are no delimiters
+	_, err3 := ParseNewEntry(`@misc{k, title = {He said "hi", ok}, note = {x}`)
+	var unbalanced *ErrUnbalancedBraces
+	if !errors.As(err3, &unbalanced) {
+		t.Errorf("Expected '%#v', but got '%#v'", &ErrUnbalancedBraces{}, err3)
+	}
+	if entry, err := ParseNewEntry(`@misc{k, title = {He said "hi", ok}, note = "{A}"}`); err != nil || entry.Fields["note"] != "{A}" {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", "{A}", entry, err)
+	}
 }
 
 func TestParseID(t *testing.T) {
@@ -191,7 +561,7 @@ func TestParseID(t *testing.T) {
 	parsedIDString, err := parseID(entry1)
 
 	if err != nil {
-		debugLog.Println(err.Error())
+		t.Errorf("Expected no error, but got '%v'", err)
 	}
 
 	if expected1 != parsedIDString {
@@ -214,8 +584,8 @@ func TestParseID(t *testing.T) {
 
 	parsedIDString2, err2 := parseID(entry2)
 
-	if err != nil {
-		debugLog.Println(err2.Error())
+	if err2 != nil {
+		t.Errorf("Expected no error, but got '%v'", err2)
 	}
 
 	if expected2 != parsedIDString2 {
@@ -238,8 +608,8 @@ func TestParseID(t *testing.T) {
 
 	parsedIDString3, err3 := parseID(entry3)
 
-	if err != nil {
-		debugLog.Println(err3.Error())
+	if err3 != nil {
+		t.Errorf("Expected no error, but got '%v'", err3)
 	}
 
 	if expected3 != parsedIDString3 {
@@ -247,6 +617,146 @@ func TestParseID(t *testing.T) {
 	}
 }
 
+func TestParseStrayCharsInValues(t *testing.T) {
+	// Case 1: '@' and '{' inside of values
+	entry1 := `@misc{doe2021, url = {http://x/@user}, note = {{@} and {\%} are fine}}`
+	expectedType1 := "misc"
+	entryType1, err1 := parseEntryType(entry1)
+	if err1 != nil || expectedType1 != entryType1 {
+		t.Errorf("Expected '%s', but got '%s' (%v)", expectedType1, entryType1, err1)
+	}
+	expectedFields1 := map[string]string{"url": "http://x/@user", "note": `{@} and {\%} are fine`}
+	fields1, _, err1b := parseFields(entry1, nil)
+	if !reflect.DeepEqual(expectedFields1, fields1) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", expectedFields1, fields1, err1b)
+	}
+
+	// Case 2: Missing '{' after the entry type must not split on a '{' inside of a value
+	entry2 := `@misc doe2021, url = {http://x/@user}}`
+	expected2 := &ErrParsingEntry{Message: fmt.Sprintf("Cannot parse entry type from this entry: %s", entry2)}
+	_, err2 := parseEntryType(entry2)
+	if err2 == nil || expected2.Error() != err2.Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, err2)
+	}
+	expected2b := &ErrParsingEntry{Message: fmt.Sprintf("Could not find '{' after the entry type: %s", entry2)}
+	_, _, err2b := parseFields(entry2, nil)
+	if err2b == nil || expected2b.Error() != err2b.Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2b, err2b)
+	}
+
+	// Case 3: Commas inside of values are no ID, a missing ID is reported by Validate
+	entry3 := `@misc{, title = {x, y, z}}`
+	id3, err3 := parseID(entry3)
+	if err3 != nil || id3 != "" {
+		t.Errorf("Expected no ID and no error, but got '%#v' (ID '%s')", err3, id3)
+	}
+}
+
+func TestParseIrregularSeparators(t *testing.T) {
+	// Case 1: Missing commas between fields are tolerated
+	entry, err := ParseNewEntry(`@misc{a, author = {X} title = {Y}, year = 2024 note = "N"url={u}}`)
+	expectedFields := map[string]string{"author": "X", "title": "Y", "year": "2024", "note": "N", "url": "u"}
+	if err != nil || !reflect.DeepEqual(expectedFields, entry.Fields) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", expectedFields, entry.Fields, err)
+	}
+	expectedWarnings := []error{&ErrMissingComma{Key: "a", Field: "title"}, &ErrMissingComma{Key: "a", Field: "note"}, &ErrMissingComma{Key: "a", Field: "url"}}
+	if !reflect.DeepEqual(expectedWarnings, entry.ParseWarnings) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedWarnings, entry.ParseWarnings)
+	}
+
+	// Case 2: Repeated commas are collapsed
+	entry, err = ParseNewEntry(`@misc{b,, title = {T},, year = {2024},,}`)
+	expectedFields = map[string]string{"title": "T", "year": "2024"}
+	if err != nil || !reflect.DeepEqual(expectedFields, entry.Fields) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", expectedFields, entry.Fields, err)
+	}
+	expectedWarnings = []error{&ErrExtraComma{Key: "b"}, &ErrExtraComma{Key: "b", Field: "title"}, &ErrExtraComma{Key: "b", Field: "year"}}
+	if !reflect.DeepEqual(expectedWarnings, entry.ParseWarnings) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedWarnings, entry.ParseWarnings)
+	}
+
+	// Case 3: A single trailing comma and '=' inside of values are regular
+	entry, _ = ParseNewEntry(`@misc{c, title = {a = b} # " and c = d", note = {N},}`)
+	if entry.ParseWarnings != nil || entry.Fields["title"] != "a = b and c = d" {
+		t.Errorf("Expected no warnings, but got '%#v' (%#v)", entry.ParseWarnings, entry.Fields)
+	}
+
+	// Case 4: The warnings are reported by Validate
+	entry, _ = ParseNewEntry(`@misc{d, note = {N} title = {T}}`)
+	errs := entry.Validate()
+	if len(errs) != 1 || !IsWarning(errs[0]) || errs[0].Error() != "Warning parsing BibTeX entry 'd': missing ',' before field 'title'" {
+		t.Errorf("Expected an ErrMissingComma warning, but got '%#v'", errs)
+	}
+}
+
+func TestParseInvalidKey(t *testing.T) {
+	// Case 1: Space in the key
+	entry1 := `@misc{doe 2021, title = {x, y}}`
+	expected1 := &ErrInvalidKey{Key: "doe 2021", BadChar: ' '}
+	_, err1 := parseID(entry1)
+	if !reflect.DeepEqual(expected1, err1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, err1)
+	}
+
+	// Case 2: Hash in the key
+	entry2 := `@misc{doe#2021, title = {x}}`
+	var invalidKey *ErrInvalidKey
+	_, errs2 := parseEntry(entry2, nil, 0, ParseOptions{})
+	if len(errs2) != 1 || !errors.As(errs2[0], &invalidKey) || invalidKey.BadChar != '#' {
+		t.Errorf("Expected an ErrInvalidKey for '#', but got '%#v'", errs2)
+	}
+
+	// Case 3: Valid keys
+	for _, key := range []string{"doe2021", "doe:2021-a", "doe_2021.b", "doe/2021"} {
+		if err := validateKey(key); err != nil {
+			t.Errorf("Expected no error for key '%s', but got '%#v'", key, err)
+		}
+	}
+}
+
+func TestParseString(t *testing.T) {
+	// Case 1: Multiple concatenated entries with a macro
+	file, err := ParseString(`@string{pub = {ACM}}@misc{a, publisher = pub}@misc{b, title = {B}}`)
+	if err != nil {
+		t.Fatalf("Expected no error, but got '%#v'", err)
+	}
+	expectedKeys := []string{"a", "b"}
+	if keys := entryKeys(file.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+	if value := file.Entries[0].Fields["publisher"]; value != "ACM" {
+		t.Errorf("Expected '%#v', but got '%#v'", "ACM", value)
+	}
+}
+
+func TestSetGetField(t *testing.T) {
+	// Case 1: The field names are normalized
+	entry := &Entry{EntryType: "misc", Key: "doe2021"}
+	entry.SetField(" Title ", "A")
+	if expected := map[string]string{"title": "A"}; !reflect.DeepEqual(expected, entry.Fields) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, entry.Fields)
+	}
+	if rawFieldName := entry.RawFieldName("title"); rawFieldName != "Title" {
+		t.Errorf("Expected '%#v', but got '%#v'", "Title", rawFieldName)
+	}
+
+	// Case 2: Lookups are normalized
+	if value, ok := entry.GetField("TITLE "); !ok || value != "A" {
+		t.Errorf("Expected '%#v', but got '%#v'", "A", value)
+	}
+	if value, ok := entry.GetField("year"); ok {
+		t.Errorf("Expected no value, but got '%#v'", value)
+	}
+
+	// Case 3: Fields are deleted with their raw field names
+	if !entry.DeleteField(" TITLE") || len(entry.Fields) != 0 || len(entry.RawFieldNames) != 0 {
+		t.Errorf("Expected no fields, but got '%#v' and '%#v'", entry.Fields, entry.RawFieldNames)
+	}
+	if entry.DeleteField("title") {
+		t.Errorf("Expected '%#v' to be deleted already", "title")
+	}
+}
+
 func TestParseBibTexFile(t *testing.T) {
 	bib := `
 	% Very useless stuff before entry that should not appear no where
@@ -315,6 +825,246 @@ func TestParseBibTexFile(t *testing.T) {
 	}
 }
 
+func TestParseBibTeXFileOffsets(t *testing.T) {
+	bib := "@string{pub = {ACM}}\n\n@misc{a, title = {Ä}}\n% Comment\n@book{b,\n  title = {B},\n  publisher = pub\n}\n"
+	file, _ := ParseString(bib)
+
+	// Case 1: The byte range of an entry in the file is its raw entry
+	for _, entry := range file.Entries {
+		if bib[entry.StartOffset:entry.EndOffset] != entry.RawEntry {
+			t.Errorf("Expected '%#v', but got '%#v'", entry.RawEntry, bib[entry.StartOffset:entry.EndOffset])
+		}
+	}
+	if file.Entries[1].StartOffset != 55 {
+		t.Errorf("Expected '%#v', but got '%#v'", 55, file.Entries[1].StartOffset)
+	}
+
+	// Case 2: The offsets of a single entry cover the whole raw entry
+	entry, _ := ParseNewEntry(`@misc{a, title = {A}}`)
+	if entry.StartOffset != 0 || entry.EndOffset != 21 {
+		t.Errorf("Expected '%#v', but got '%#v'", []int{0, 21}, []int{entry.StartOffset, entry.EndOffset})
+	}
+}
+
+func TestParseFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.bib")
+	second := filepath.Join(dir, "second.bib")
+	os.WriteFile(first, []byte("@string{pub = {ACM}}\n@book{a, title = {A}, publisher = pub}\n@misc{b, title = {B}"), 0o644)
+	os.WriteFile(second, []byte("@misc{a, title = {Other A}, publisher = pub}\n@comment{@misc{c, title = {C}}}"), 0o644)
+	missing := filepath.Join(dir, "missing.bib")
+
+	// Case 1: The entries of all files are concatenated and tagged with their file
+	file, errs := ParseFiles(first, missing, second)
+	var sources []string
+	for _, entry := range file.Entries {
+		sources = append(sources, entry.Key+"@"+filepath.Base(entry.SourceFile))
+	}
+	expectedSources := []string{"a@first.bib", "a@second.bib"}
+	if !reflect.DeepEqual(expectedSources, sources) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedSources, sources)
+	}
+	if file.DisabledEntries[0].SourceFile != second {
+		t.Errorf("Expected '%#v', but got '%#v'", second, file.DisabledEntries[0].SourceFile)
+	}
+
+	// Case 2: Macros of previous files are resolved
+	if file.Entries[1].Fields["publisher"] != "ACM" {
+		t.Errorf("Expected '%#v', but got '%#v'", "ACM", file.Entries[1].Fields["publisher"])
+	}
+
+	// Case 3: Duplicate keys across files are kept
+	if duplicates := file.DuplicateKeys(); !reflect.DeepEqual(map[string][]int{"a": {0, 1}}, duplicates) {
+		t.Errorf("Expected '%#v', but got '%#v'", map[string][]int{"a": {0, 1}}, duplicates)
+	}
+
+	// Case 4: Parsing errors are collected with their file, unreadable files are skipped
+	var inFile *ErrInFile
+	if len(file.Errors) != 1 || !errors.As(file.Errors[0], &inFile) || inFile.Path != first {
+		t.Errorf("Expected an ErrInFile of '%s', but got '%#v'", first, file.Errors)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], os.ErrNotExist) {
+		t.Errorf("Expected a missing file, but got '%#v'", errs)
+	}
+}
+
+// cancelingReader cancels its context as soon as it is read from.
+type cancelingReader struct {
+	reader io.Reader
+	cancel context.CancelFunc
+}
+
+func (r *cancelingReader) Read(p []byte) (int, error) {
+	r.cancel()
+	return r.reader.Read(p)
+}
+
+func TestParseNewBibTeXFileContext(t *testing.T) {
+	bib := "@misc{a, title = {A}}\n@misc{b, title = {B}}"
+
+	// Case 1: A context that is not done parses the whole file
+	file1, err1 := ParseNewBibTeXFileContext(context.Background(), strings.NewReader(bib))
+	if err1 != nil || !reflect.DeepEqual([]string{"a", "b"}, entryKeys(file1.Entries)) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", []string{"a", "b"}, file1, err1)
+	}
+
+	// Case 2: Canceled while reading
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	file2, err2 := ParseNewBibTeXFileContext(ctx, &cancelingReader{reader: strings.NewReader(bib), cancel: cancel})
+	if file2 != nil || !errors.Is(err2, context.Canceled) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", context.Canceled, file2, err2)
+	}
+
+	// Case 3: Deadline exceeded before parsing
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	if _, err3 := ParseNewBibTeXFileContext(expired, strings.NewReader(bib)); !errors.Is(err3, context.DeadlineExceeded) {
+		t.Errorf("Expected '%#v', but got '%#v'", context.DeadlineExceeded, err3)
+	}
+}
+
+func TestParseBibTeXFileFailFast(t *testing.T) {
+	bib := `@misc{a, title = {A}}
+@misc{b c, title = {B}}
+@misc{c, title = {C}}
+@misc{d, title = {D}`
+
+	// Case 1: The first error stops the parsing after its entry
+	file, err := ParseNewBibTeXFileWithOptions(strings.NewReader(bib), ParseOptions{FailFast: true})
+	var invalidKey *ErrInvalidKey
+	if !errors.As(err, &invalidKey) {
+		t.Errorf("Expected an ErrInvalidKey, but got '%#v'", err)
+	}
+	if len(file.Entries) != 2 || len(file.Errors) != 1 {
+		t.Errorf("Expected 2 entries and 1 error, but got '%#v' and '%#v'", file.Entries, file.Errors)
+	}
+
+	// Case 2: All errors are collected by default
+	file, err = ParseNewBibTeXFile(strings.NewReader(bib))
+	if err != nil || len(file.Entries) != 3 || len(file.Errors) != 2 {
+		t.Errorf("Expected 3 entries and 2 errors, but got '%#v' and '%#v' (%v)", file.Entries, file.Errors, err)
+	}
+}
+
+func TestParseBibTeXFileErrors(t *testing.T) {
+	bib := `@misc{doe2021, title = {Valid}}
+
+@misc{smith2020,
+  title = {A} {B}
+}
+@misc{roe2022, publisher = undefined}
+@misc{, title = {No key}}
+`
+	reader := strings.NewReader(bib)
+	parsedBibTeXFile, err := ParseNewBibTeXFile(reader)
+	if err != nil {
+		t.Errorf("Expected no error, but got '%#v'", err)
+	}
+	expectedErrors := []error{
+		// Rejected field values are reported with their own line
+		&ErrParsingEntry{Message: "The outer braces should enclose the whole field value: {A} {B}", Line: 4},
+		&ErrUndefinedMacro{Name: "undefined"},
+	}
+	if !reflect.DeepEqual(expectedErrors, parsedBibTeXFile.Errors) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedErrors, parsedBibTeXFile.Errors)
+	}
+	// Entries are still added if only fields or key could not be parsed
+	expectedEntryNumber := 4
+	if expectedEntryNumber != len(parsedBibTeXFile.Entries) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedEntryNumber, len(parsedBibTeXFile.Entries))
+	}
+
+	// A missing key is no parsing error, but reported by Validate (see TestValidateMissingKey)
+	entry, err2 := ParseNewEntry(`@misc{, title = {No key}}`)
+	if entry == nil || entry.Fields["title"] != "No key" {
+		t.Errorf("Expected parsed entry, but got '%#v'", entry)
+	}
+	if err2 != nil {
+		t.Errorf("Expected no error, but got '%#v'", err2)
+	}
+}
+
+func TestParseBibTeXFileMultiLineValues(t *testing.T) {
+	bib := `
+@article{smith2021ai,
+  author       = {John Smith and
+                  Alice Johnson},
+  title        = {Advancements in AI
+                  for Natural Language Processing}, % comment
+  url          = {http://example.com/@smith},
+  year         = {2021}
+}
+`
+	reader := strings.NewReader(bib)
+	parsedBibTeXFile, _ := ParseNewBibTeXFile(reader)
+	expectedFields := map[string]string{"author": "John Smith and Alice Johnson", "title": "Advancements in AI for Natural Language Processing", "url": "http://example.com/@smith", "year": "2021"}
+	if len(parsedBibTeXFile.Entries) != 1 || !reflect.DeepEqual(expectedFields, parsedBibTeXFile.Entries[0].Fields) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedFields, parsedBibTeXFile.Entries)
+	}
+}
+
+func TestParseBibTeXFileCommentsAndPreamble(t *testing.T) {
+	bib := `
+@preamble{"\newcommand{\noopsort}[1]{}"}
+@Comment{This file was exported by a reference manager.}
+@misc{doe2021, title = {Misc}}
+@comment{
+  Parked: @misc{roe2022, title = {Later}}
+}
+@PREAMBLE{"\newcommand{\SortNoop}[1]{}"}
+`
+	reader := strings.NewReader(bib)
+	parsedBibTeXFile, _ := ParseNewBibTeXFile(reader)
+	expectedComments := []string{"This file was exported by a reference manager.", "Parked: @misc{roe2022, title = {Later}}"}
+	if !reflect.DeepEqual(expectedComments, parsedBibTeXFile.Comments) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedComments, parsedBibTeXFile.Comments)
+	}
+	expectedPreamble := `"\newcommand{\noopsort}[1]{}" # "\newcommand{\SortNoop}[1]{}"`
+	if expectedPreamble != parsedBibTeXFile.Preamble {
+		t.Errorf("Expected '%s', but got '%s'", expectedPreamble, parsedBibTeXFile.Preamble)
+	}
+	if len(parsedBibTeXFile.Entries) != 1 || parsedBibTeXFile.Entries[0].Key != "doe2021" {
+		t.Errorf("Expected only entry 'doe2021', but got '%#v'", parsedBibTeXFile.Entries)
+	}
+	if len(parsedBibTeXFile.Errors) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", parsedBibTeXFile.Errors)
+	}
+	// Entries inside of @comment blocks are disabled entries
+	if len(parsedBibTeXFile.DisabledEntries) != 1 || parsedBibTeXFile.DisabledEntries[0].Fields["title"] != "Later" {
+		t.Errorf("Expected only disabled entry 'roe2022', but got '%#v'", parsedBibTeXFile.DisabledEntries)
+	}
+}
+
+func TestParseBibTeXFileMacros(t *testing.T) {
+	bib := `
+@string{pub = {ACM Press}}
+@STRING{ny = "New York"}
+
+@book{acm2024,
+  author       = {Max Mustermann},
+  title        = {Macros in BibTeX},
+  publisher    = pub,
+  address      = ny # ", USA",
+  year         = 2024
+}
+`
+	reader := strings.NewReader(bib)
+	parsedBibTeXFile, _ := ParseNewBibTeXFile(reader)
+	expectedMacros := map[string]string{"pub": "ACM Press", "ny": "New York"}
+	if !reflect.DeepEqual(expectedMacros, parsedBibTeXFile.Macros) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedMacros, parsedBibTeXFile.Macros)
+	}
+	expectedEntryNumber := 1
+	if expectedEntryNumber != len(parsedBibTeXFile.Entries) {
+		t.Fatalf("Expected '%#v', but got '%#v'", expectedEntryNumber, len(parsedBibTeXFile.Entries))
+	}
+	expectedFields := map[string]string{"author": "Max Mustermann", "title": "Macros in BibTeX", "publisher": "ACM Press", "address": "New York, USA", "year": "2024"}
+	if !reflect.DeepEqual(expectedFields, parsedBibTeXFile.Entries[0].Fields) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedFields, parsedBibTeXFile.Entries[0].Fields)
+	}
+}
+
 func testParseURL(t *testing.T) {
 
 	bib := `
@@ -351,3 +1101,352 @@ func testParseURL(t *testing.T) {
 		t.Errorf("Expected '%s', but got '%s'", expextedURL, entryURL)
 	}
 }
+
+func TestParseParenthesizedEntries(t *testing.T) {
+	// Case 1: Entry body delimited by ()
+	entry1, err1 := ParseNewEntry(`@article(smith2021,
+  author  = {John Smith},
+  title   = {Parentheses (like these) and a ) in braces},
+  journal = "Journal (Online)",
+  year    = 2021
+)`)
+	expected1 := map[string]string{
+		"author":  "John Smith",
+		"title":   "Parentheses (like these) and a ) in braces",
+		"journal": "Journal (Online)",
+		"year":    "2021",
+	}
+	if err1 != nil || entry1.Key != "smith2021" || entry1.EntryType != "article" {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", "smith2021", entry1, err1)
+	}
+	if entry1 != nil && !reflect.DeepEqual(expected1, entry1.Fields) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, entry1.Fields)
+	}
+
+	// Case 2: Missing closing ')'
+	expected2 := &ErrParsingEntry{Message: "The last char in fields list should be ')'."}
+	_, _, err2 := parseFields(`@misc(id, title = {A}}`, nil)
+	if err2 == nil || expected2.Error() != err2.Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, err2)
+	}
+
+	// Case 3: Parenthesized entries, macros, and comments in a BibTeX file
+	bib := `@string(pub = "ACM Press")
+@comment(Exported {(by hand)})
+@book(knuth1997, title = {The Art (Vol. 1)}, publisher = pub, year = 1997)
+@misc{doe2021, title = {Braces}}`
+	bibtexFile, err3 := ParseNewBibTeXFile(strings.NewReader(bib))
+	if err3 != nil || len(bibtexFile.Errors) != 0 {
+		t.Errorf("Expected no errors, but got '%#v' and '%#v'", err3, bibtexFile.Errors)
+	}
+	expectedKeys := []string{"knuth1997", "doe2021"}
+	if keys := entryKeys(bibtexFile.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+	if entry, ok := bibtexFile.LookupByKey("knuth1997"); !ok || entry.Fields["publisher"] != "ACM Press" || entry.Fields["title"] != "The Art (Vol. 1)" {
+		t.Errorf("Expected '%#v', but got '%#v'", "ACM Press", entry)
+	}
+	expectedComments := []string{"Exported {(by hand)}"}
+	if !reflect.DeepEqual(expectedComments, bibtexFile.Comments) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedComments, bibtexFile.Comments)
+	}
+}
+
+func TestParseInvisibleLeadingCharacters(t *testing.T) {
+	// Case 1: A file starting with a UTF-8 byte order mark
+	file, err := os.Open("testdata/bom.bib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	bibtexFile, err1 := ParseNewBibTeXFile(file)
+	if err1 != nil || len(bibtexFile.Errors) != 0 {
+		t.Errorf("Expected no errors, but got '%#v' and '%#v'", err1, bibtexFile.Errors)
+	}
+	expectedKeys1 := []string{"bom2024", "second2024"}
+	if keys := entryKeys(bibtexFile.Entries); !reflect.DeepEqual(expectedKeys1, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys1, keys)
+	}
+	// The offsets still count the three bytes of the byte order mark
+	if len(bibtexFile.Entries) > 0 && bibtexFile.Entries[0].StartOffset != 34 {
+		t.Errorf("Expected '%#v', but got '%#v'", 34, bibtexFile.Entries[0].StartOffset)
+	}
+
+	// Case 2: Byte order mark, zero-width, and non-breaking spaces before the '@' of an entry
+	for _, prefix := range []string{"\ufeff", "\u200b", "\u00a0", " \u2060\t"} {
+		entry, err2 := ParseNewEntry(prefix + "@misc{key2, title = {Invisible}}")
+		if err2 != nil || entry.EntryType != "misc" || entry.Key != "key2" || entry.Fields["title"] != "Invisible" {
+			t.Errorf("Expected '%#v', but got '%#v' (%v)", "key2", entry, err2)
+		}
+	}
+
+	// Case 3: A byte order mark directly before the first entry of the input
+	bibtexFile3, _ := ParseString("\ufeff@misc{key3, title = {First}}")
+	if len(bibtexFile3.Entries) != 1 || bibtexFile3.Entries[0].RawEntry != "@misc{key3, title = {First}}" {
+		t.Errorf("Expected '%#v', but got '%#v'", "key3", bibtexFile3.Entries)
+	}
+}
+
+func TestParseIndentedEntries(t *testing.T) {
+	// Case 1: A file whose entries, @string macros, and @comment blocks are indented
+	file, err := os.Open("testdata/indented.bib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	bibtexFile, err1 := ParseNewBibTeXFile(file)
+	if err1 != nil || len(bibtexFile.Errors) != 0 {
+		t.Errorf("Expected no errors, but got '%#v' and '%#v'", err1, bibtexFile.Errors)
+	}
+	expectedKeys := []string{"first2024", "second2023", "third2022", "fourth2021"}
+	if keys := entryKeys(bibtexFile.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+	if len(bibtexFile.DisabledEntries) != 1 || bibtexFile.Macros["acm"] != "ACM Press" {
+		t.Errorf("Expected the disabled entry and the macro, but got '%#v' and '%#v'", bibtexFile.DisabledEntries, bibtexFile.Macros)
+	}
+	// The entries start at their '@', after the indentation
+	expectedLines := []int{5, 12, 18, 25}
+	for i, entry := range bibtexFile.Entries {
+		if i < len(expectedLines) && (entry.Line != expectedLines[i] || !strings.HasPrefix(entry.RawEntry, "@")) {
+			t.Errorf("Expected line %d, but got '%#v'", expectedLines[i], entry)
+		}
+	}
+
+	// Case 2: The raw entries split from the file
+	if rawEntries := fixtureEntries(t, "testdata/indented.bib"); len(rawEntries) != 6 {
+		t.Errorf("Expected '%#v', but got '%#v'", 6, rawEntries)
+	}
+}
+
+func TestParseNewEntryWithOptions(t *testing.T) {
+	// Müller with a decomposed ü (u + combining diaeresis)
+	raw := "@misc{id, author = {Mu\u0308ller}}"
+	// Case 1: Values are kept as they are by default
+	entry1, _ := ParseNewEntry(raw)
+	if entry1.Fields["author"] != "Mu\u0308ller" {
+		t.Errorf("Expected '%#v', but got '%#v'", "Mu\u0308ller", entry1.Fields["author"])
+	}
+	// Case 2: NFC normalization
+	entry2, _ := ParseNewEntryWithOptions(raw, ParseOptions{NormalizeNFC: true})
+	if entry2.Fields["author"] != "M\u00fcller" {
+		t.Errorf("Expected '%#v', but got '%#v'", "M\u00fcller", entry2.Fields["author"])
+	}
+	// Case 3: NFC normalization of a file, including macros
+	bibtexFile, _ := ParseNewBibTeXFileWithOptions(strings.NewReader("@string{pub = {Verlag Mu\u0308ller}}\n"+raw+"\n@misc{b, publisher = pub}"), ParseOptions{NormalizeNFC: true})
+	if bibtexFile.Entries[0].Fields["author"] != "M\u00fcller" || bibtexFile.Entries[1].Fields["publisher"] != "Verlag M\u00fcller" {
+		t.Errorf("Expected '%#v', but got '%#v'", "M\u00fcller", bibtexFile.Entries[0].Fields)
+	}
+}
+
+func TestParseFieldPositions(t *testing.T) {
+	raw := "@article{key,\n  Author = {Jane\n    Doe}, % comment\n\n  title={T},year = 2024,\n  title = {Again}\n}"
+	// Case 1: The offsets of the field names in the raw entry, the last one for repeated fields
+	entry, _ := ParseNewEntryWithOptions(raw, ParseOptions{FieldPositions: true})
+	expected := map[string]int{
+		"author": strings.Index(raw, "Author"),
+		"title":  strings.Index(raw, "title = {Again}"),
+		"year":   strings.Index(raw, "year"),
+	}
+	if !reflect.DeepEqual(expected, entry.FieldPositions) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, entry.FieldPositions)
+	}
+
+	// Case 2: The offsets are relative to the start of the entry in the file
+	bibtexFile, _ := ParseNewBibTeXFileWithOptions(strings.NewReader("% First entry\n  @misc{a, note = {N}}"), ParseOptions{FieldPositions: true})
+	if got := bibtexFile.Entries[0].FieldPositions["note"]; got != 9 || bibtexFile.Entries[0].RawEntry[got:got+4] != "note" {
+		t.Errorf("Expected '%#v', but got '%#v'", 9, got)
+	}
+
+	// Case 3: Not recorded by default or for rejected fields
+	entry3, _ := ParseNewEntry(raw)
+	entry4, _ := ParseNewEntryWithOptions(`@misc{a, title = {T}, year = undefined}`, ParseOptions{FieldPositions: true})
+	expected4 := map[string]int{"title": 9}
+	if entry3.FieldPositions != nil || !reflect.DeepEqual(expected4, entry4.FieldPositions) {
+		t.Errorf("Expected no positions and '%#v', but got '%#v' and '%#v'", expected4, entry3.FieldPositions, entry4.FieldPositions)
+	}
+}
+
+func TestParseRejectedValues(t *testing.T) {
+	// Case 1: Only the field with an undefined macro is left out
+	entry1, err1 := ParseNewEntry(`@article{a, author = {Jane Doe}, title = {T}, journal = undefined, year = {2020}}`)
+	expected1 := map[string]string{"author": "Jane Doe", "title": "T", "year": "2020"}
+	if !reflect.DeepEqual(expected1, entry1.Fields) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, entry1.Fields)
+	}
+	var undefinedErr *ErrUndefinedMacro
+	if !errors.As(err1, &undefinedErr) || undefinedErr.Name != "undefined" {
+		t.Errorf("Expected '%#v', but got '%#v'", &ErrUndefinedMacro{Name: "undefined"}, err1)
+	}
+	expectedErrs1 := []error{&ErrMissingField{EntryType: "article", Field: "journal", Key: "a"}}
+	if errs := entry1.Validate(); !reflect.DeepEqual(expectedErrs1, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedErrs1, errs)
+	}
+
+	// Case 2: An unclosed quote only drops the fields it swallows
+	entry2, err2 := ParseNewEntry(`@misc{a, title = {T}, note = "unclosed, year = {2020}}`)
+	expected2 := map[string]string{"title": "T"}
+	if err2 == nil || !reflect.DeepEqual(expected2, entry2.Fields) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", expected2, entry2.Fields, err2)
+	}
+
+	// Case 3: Each rejected value is reported with its line, the fields after it are kept
+	bib := "@misc{a,\n  title = {A} {B},\n  note = undefined,\n  year = {2020}\n}\n"
+	file, _ := ParseString(bib)
+	expectedErrs3 := []error{
+		&ErrParsingEntry{Message: "The outer braces should enclose the whole field value: {A} {B}", Line: 2},
+		&ErrUndefinedMacro{Name: "undefined"},
+	}
+	if !reflect.DeepEqual(expectedErrs3, file.Errors) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedErrs3, file.Errors)
+	}
+	if expected3 := map[string]string{"year": "2020"}; len(file.Entries) != 1 || !reflect.DeepEqual(expected3, file.Entries[0].Fields) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, file.Entries)
+	}
+}
+
+func TestParseRepairMissingBrace(t *testing.T) {
+	bib := "@misc{a, title = {A}}\n\n@article{b,\n  title = {B},\n  year = {2020}, % last field\n\n"
+	// Case 1: The missing '}' of the last entry is added and reported
+	bibtexFile, _ := ParseNewBibTeXFileWithOptions(strings.NewReader(bib), ParseOptions{RepairMissingBrace: true})
+	if len(bibtexFile.Errors) != 0 || len(bibtexFile.Entries) != 2 {
+		t.Fatalf("Expected two entries without errors, but got '%#v' and '%#v'", bibtexFile.Entries, bibtexFile.Errors)
+	}
+	entry := bibtexFile.Entries[1]
+	expected := []error{&ErrMissingClosingBrace{Key: "b", Line: 5}}
+	if !reflect.DeepEqual(expected, entry.ParseWarnings) || entry.Fields["year"] != "2020" {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, entry.ParseWarnings)
+	}
+	if !strings.HasSuffix(entry.RawEntry, "% last field\n}") {
+		t.Errorf("Expected the '}' in a new line, but got '%#v'", entry.RawEntry)
+	}
+	expectedMessage := "Warning parsing BibTeX entry 'b' (line 5): the closing '}' of the entry is missing and was added"
+	if errs := entry.Validate(); len(errs) == 0 || errs[len(errs)-1].Error() != expectedMessage || !IsWarning(errs[len(errs)-1]) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedMessage, errs)
+	}
+
+	// Case 2: Without the option, the entry is rejected
+	bibtexFile2, _ := ParseString(bib)
+	if len(bibtexFile2.Errors) != 1 || len(bibtexFile2.Entries) != 1 {
+		t.Errorf("Expected the last entry to be rejected, but got '%#v'", bibtexFile2.Errors)
+	}
+
+	// Case 3: Entries missing more than one brace or with other unbalanced braces are not repaired
+	for _, raw := range []string{"@misc{a, title = {A", "@misc{a, title = {A}}}", "@misc(a, title = {A}", "@misc{a, title = {A}}"} {
+		entry, _ := ParseNewEntryWithOptions(raw, ParseOptions{RepairMissingBrace: true})
+		if entry != nil && (len(entry.ParseWarnings) != 0 || entry.RawEntry != raw) {
+			t.Errorf("Expected '%#v' not to be repaired, but got '%#v'", raw, entry)
+		}
+	}
+
+	// Case 4: A '}' missing in the middle of the file is not repaired, the entry is
+	// rejected like without the option
+	bib4 := "@misc{a, title = {A}, year = {2020}\n\n@misc{b, title = {B}}\n"
+	repaired4, _ := ParseNewBibTeXFileWithOptions(strings.NewReader(bib4), ParseOptions{RepairMissingBrace: true})
+	unrepaired4, _ := ParseString(bib4)
+	if !reflect.DeepEqual(unrepaired4.Errors, repaired4.Errors) || len(repaired4.Entries) != 0 {
+		t.Errorf("Expected '%#v', but got '%#v' and '%#v'", unrepaired4.Errors, repaired4.Errors, repaired4.Entries)
+	}
+	var unbalanced4 *ErrUnbalancedBraces
+	if len(repaired4.Errors) != 1 || !errors.As(repaired4.Errors[0], &unbalanced4) || !unbalanced4.Unclosed || unbalanced4.Line != 1 {
+		t.Errorf("Expected an unclosed brace in line 1, but got '%#v'", repaired4.Errors)
+	}
+}
+
+// benchmarkEntries returns the raw entries of the example bibliography.bib.
+func benchmarkEntries(b *testing.B) []string {
+	return fixtureEntries(b, "../bibliography.bib")
+}
+
+// fixtureEntries returns the raw entries of the BibTeX file at path.
+func fixtureEntries(tb testing.TB, path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	rawEntries, err := SplitEntries(strings.NewReader(string(data)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return rawEntries
+}
+
+// FuzzParseNewEntry checks that ParseNewEntry never panics on malformed input and that
+// parsed entries can be parsed again from their formatted output (see Entry.String).
+func FuzzParseNewEntry(f *testing.F) {
+	for _, path := range []string{"../bibliography.bib", "testdata/bom.bib", "testdata/indented.bib"} {
+		for _, rawEntry := range fixtureEntries(f, path) {
+			f.Add(rawEntry)
+		}
+	}
+	for _, rawEntry := range []string{
+		"", "@", "@{", "@misc{", "@misc{,}", "@misc{key,", "@misc(key, title = {A}",
+		"@misc{key, title = }", "@misc{key, title = {A}} title = {B}}", "@misc{key, = {A}}",
+		`@misc{key, title = "A {"} B"}`, "@misc{key, title = {Ä \\{ ü}, year = 2024 # a}",
+		"@misc{key author = {A} title = {B}}", "@misc{key,, title = {A},,}", "\ufeff@misc{k, t = {\u200b}}",
+	} {
+		f.Add(rawEntry)
+	}
+	f.Fuzz(func(t *testing.T, rawEntry string) {
+		entry, err := ParseNewEntry(rawEntry)
+		if err != nil || entry == nil || entry.Key == "" {
+			return
+		}
+		formatted := entry.String()
+		reparsed, err := ParseNewEntry(formatted)
+		if err != nil {
+			t.Fatalf("Expected no error for the formatted entry '%s', but got '%v'", formatted, err)
+		}
+		if reparsed.Key != entry.Key || reparsed.EntryType != entry.EntryType {
+			t.Errorf("Expected '%#v', but got '%#v'", entry.Key, reparsed.Key)
+		}
+	})
+}
+
+func BenchmarkParseFields(b *testing.B) {
+	rawEntries := benchmarkEntries(b)
+	cleanEntries := make([]string, len(rawEntries))
+	for i, rawEntry := range rawEntries {
+		cleanEntries[i] = cleanRawEntry(rawEntry)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		for _, cleanEntry := range cleanEntries {
+			parseFields(cleanEntry, defaultMacros)
+		}
+	}
+}
+
+// BenchmarkParseFieldsAdversarial parses entries shaped to slow down the search of field
+// names, e.g., long runs of letters and white spaces before an '='. The time per byte
+// should be the same for all shapes.
+func BenchmarkParseFieldsAdversarial(b *testing.B) {
+	shapes := map[string]string{
+		"letters":     strings.Repeat("ab ", 10000) + "= {x}",
+		"no equals":   strings.Repeat("ab ", 10000),
+		"equals":      strings.Repeat("a=", 10000),
+		"fields":      strings.Repeat("a = b ", 10000),
+		"bare values": "title = " + strings.Repeat("ab ", 10000),
+	}
+	for name, body := range shapes {
+		cleanEntry := cleanRawEntry("@misc{key, " + body + "}")
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(cleanEntry)))
+			for range b.N {
+				parseFields(cleanEntry, defaultMacros)
+			}
+		})
+	}
+}
+
+func BenchmarkParseNewEntry(b *testing.B) {
+	rawEntries := benchmarkEntries(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		for _, rawEntry := range rawEntries {
+			ParseNewEntry(rawEntry)
+		}
+	}
+}
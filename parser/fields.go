@@ -0,0 +1,365 @@
+// The fields.go source file includes validators for the values of single BibTeX fields
+//
+// validateDOI: checks the format of a DOI
+// ResolveDOI: checks if a DOI is registered at doi.org
+// validateISBN: checks the check digit of an ISBN-10 or ISBN-13
+// validatePages: checks the style and order of page ranges
+// validateYear: checks that a year is a 4-digit number
+// validateVolume, validateNumber: check that volumes and numbers are numeric
+// validateURL: checks that a URL has a scheme and a host
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Define errors
+type ErrInvalidDOI struct {
+	Key   string
+	Value string
+}
+
+type ErrUnresolvedDOI struct {
+	Key   string
+	Value string
+}
+
+type ErrInvalidISBN struct {
+	Key   string
+	Value string
+}
+
+type ErrPagesHyphen struct {
+	Key   string
+	Value string
+}
+
+type ErrReversedPages struct {
+	Key   string
+	Value string
+}
+
+type ErrInvalidURL struct {
+	Key   string
+	Value string
+}
+
+type ErrSuspiciousValue struct {
+	Key      string
+	Field    string
+	Value    string
+	Expected string // A description of the expected values, e.g., "a 4-digit year".
+}
+
+func (e *ErrInvalidDOI) Error() string {
+	return fmt.Sprintf("Error validating BibTeX entry '%s': malformed DOI '%s'", e.Key, e.Value)
+}
+
+func (e *ErrUnresolvedDOI) Error() string {
+	return fmt.Sprintf("Error validating BibTeX entry '%s': DOI '%s' does not resolve", e.Key, e.Value)
+}
+
+func (e *ErrInvalidISBN) Error() string {
+	return fmt.Sprintf("Error validating BibTeX entry '%s': invalid ISBN '%s'", e.Key, e.Value)
+}
+
+func (e *ErrPagesHyphen) Error() string {
+	return fmt.Sprintf("Warning validating BibTeX entry '%s': page range '%s' should use '--'", e.Key, e.Value)
+}
+
+func (e *ErrReversedPages) Error() string {
+	return fmt.Sprintf("Error validating BibTeX entry '%s': start page of '%s' exceeds the end page", e.Key, e.Value)
+}
+
+func (e *ErrInvalidURL) Error() string {
+	return fmt.Sprintf("Error validating BibTeX entry '%s': invalid URL '%s'", e.Key, e.Value)
+}
+
+func (e *ErrSuspiciousValue) Error() string {
+	return fmt.Sprintf("Warning validating BibTeX entry '%s': %s '%s' does not look like %s", e.Key, e.Field, e.Value, e.Expected)
+}
+
+// fieldValidators maps field names to functions checking their values.
+// The functions get the key of the entry and the field value and return nil if the value is valid.
+var fieldValidators = map[string]func(key, value string) error{
+	"doi":    validateDOI,
+	"isbn":   validateISBN,
+	"month":  validateMonth,
+	"number": validateNumber,
+	"pages":  validatePages,
+	"url":    validateURL,
+	"volume": validateVolume,
+	"year":   validateYear,
+}
+
+// knownFields contains the standard BibTeX fields, the biblatex fields, and
+// common fields of reference managers (e.g., abstract, keywords, doi).
+var knownFields = mergeFieldSets(bibtexFields, biblatexFields, sortingFields, commonFields)
+
+// bibtexFields contains the fields of the standard BibTeX styles.
+var bibtexFields = map[string]bool{
+	"address": true, "annote": true, "author": true, "booktitle": true, "chapter": true,
+	"crossref": true, "edition": true, "editor": true, "howpublished": true, "institution": true,
+	"journal": true, "key": true, "month": true, "note": true, "number": true,
+	"organization": true, "pages": true, "publisher": true, "school": true, "series": true,
+	"title": true, "type": true, "volume": true, "year": true,
+}
+
+// biblatexFields contains the data fields and special fields of biblatex.
+var biblatexFields = map[string]bool{
+	"addendum": true, "afterword": true, "annotation": true, "annotator": true, "bookauthor": true,
+	"bookpagination": true, "booksubtitle": true, "booktitleaddon": true, "commentator": true,
+	"date": true, "editora": true, "editorb": true, "editorc": true, "editortype": true,
+	"eid": true, "entryset": true, "eprintclass": true, "eprinttype": true,
+	"eventdate": true, "eventtitle": true, "eventtitleaddon": true, "execute": true, "file": true,
+	"foreword": true, "holder": true, "ids": true, "indexsorttitle": true, "indextitle": true,
+	"introduction": true, "isan": true, "ismn": true, "isrn": true, "issue": true,
+	"issuesubtitle": true, "issuetitle": true, "iswc": true, "journalsubtitle": true,
+	"journaltitle": true, "label": true, "langid": true, "langidopts": true, "language": true,
+	"library": true, "location": true, "mainsubtitle": true, "maintitle": true,
+	"maintitleaddon": true, "nameaddon": true, "options": true, "origdate": true,
+	"origlanguage": true, "origlocation": true, "origpublisher": true, "origtitle": true,
+	"pagetotal": true, "pagination": true, "part": true, "pubstate": true,
+	"related": true, "relatedoptions": true, "relatedstring": true, "relatedtype": true,
+	"reprinttitle": true, "shortauthor": true, "shorteditor": true, "shorthand": true,
+	"shorthandintro": true, "shortjournal": true, "shortseries": true, "shorttitle": true,
+	"subtitle": true, "titleaddon": true, "translator": true, "urldate": true,
+	"venue": true, "version": true, "volumes": true, "xdata": true, "xref": true,
+}
+
+// sortingFields contains the biblatex fields that control the order of the bibliography,
+// see SortKey. BibTeX ignores them, so they are accepted by all profiles to keep the order
+// of files that are shared between BibTeX and biblatex users.
+var sortingFields = map[string]bool{
+	"presort": true, "sortkey": true, "sortname": true, "sortshorthand": true, "sorttitle": true,
+	"sortyear": true,
+}
+
+// commonFields contains common fields of reference managers, which are used by many styles,
+// including the arXiv fields eprint, archiveprefix, and primaryclass (see ArxivURL).
+var commonFields = map[string]bool{
+	"abstract": true, "archiveprefix": true, "doi": true, "eprint": true, "isbn": true,
+	"issn": true, "keywords": true, "primaryclass": true, "url": true,
+}
+
+// mergeFieldSets returns the union of the field sets.
+func mergeFieldSets(fieldSets ...map[string]bool) map[string]bool {
+	merged := make(map[string]bool)
+	for _, fieldSet := range fieldSets {
+		for fieldName := range fieldSet {
+			merged[fieldName] = true
+		}
+	}
+	return merged
+}
+
+// Regex to match a DOI like 10.1000/182 (without a resolver prefix like https://doi.org/)
+var regexDOI = regexp.MustCompile(`^10\.\d{4,9}/\S+$`)
+
+// The handle API of doi.org used by ResolveDOI
+var doiHandleAPI = "https://doi.org/api/handles/"
+
+// validateDOI checks if the value is a DOI following the standard pattern 10.<registrant>/<suffix>.
+func validateDOI(key, value string) error {
+	if !regexDOI.MatchString(strings.TrimSpace(value)) {
+		return &ErrInvalidDOI{Key: key, Value: value}
+	}
+	return nil
+}
+
+// ResolveDOI checks via the doi.org handle API if the given DOI is registered.
+// It returns false (and no error) if the DOI does not exist. An error is returned
+// if the request fails or the API returns an unexpected response.
+// ResolveDOI requires network access and is therefore not part of Validate,
+// see ValidateOptions.
+func ResolveDOI(ctx context.Context, doi string) (bool, error) {
+	// Escape the DOI but keep the '/' separators
+	segments := strings.Split(strings.TrimSpace(doi), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doiHandleAPI+strings.Join(segments, "/"), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	// The API returns the responseCode 1 for existing and 100 for unknown handles
+	var handle struct {
+		ResponseCode int `json:"responseCode"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&handle); err != nil {
+		return false, fmt.Errorf("resolving DOI '%s': %w", doi, err)
+	}
+	switch handle.ResponseCode {
+	case 1:
+		return true, nil
+	case 100:
+		return false, nil
+	default:
+		return false, fmt.Errorf("resolving DOI '%s': unexpected response code %d (HTTP %d)", doi, handle.ResponseCode, resp.StatusCode)
+	}
+}
+
+// validateISBN checks if the value is an ISBN-10 or ISBN-13 with a correct check digit.
+// Hyphens and white spaces are ignored. The check digit of an ISBN-10 may be 'X' (for 10).
+func validateISBN(key, value string) error {
+	isbn := strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(value))
+	var valid bool
+	switch len(isbn) {
+	case 10:
+		valid = validISBN10(isbn)
+	case 13:
+		valid = validISBN13(isbn)
+	}
+	if !valid {
+		return &ErrInvalidISBN{Key: key, Value: value}
+	}
+	return nil
+}
+
+// Regex to match a numeric page range with a single hyphen like 123-145
+var regexSingleHyphenRange = regexp.MustCompile(`^\d+\s*-\s*\d+$`)
+
+// validatePages checks the page ranges (e.g., 123--145 or 1--5, 7--9) of the value.
+// A numeric range with a single hyphen (123-145) is reported as an ErrPagesHyphen
+// warning, a range with a start page after its end page (145--123) as an ErrReversedPages.
+// Non-numeric pages (e.g., xii--xv or e12345) are not checked for their order.
+func validatePages(key, value string) error {
+	for _, pageRange := range strings.Split(value, ",") {
+		pageRange = strings.TrimSpace(pageRange)
+		if regexSingleHyphenRange.MatchString(pageRange) {
+			return &ErrPagesHyphen{Key: key, Value: value}
+		}
+		startPage, endPage, found := strings.Cut(pageRange, "--")
+		if !found {
+			continue
+		}
+		start, errStart := strconv.Atoi(strings.TrimSpace(startPage))
+		end, errEnd := strconv.Atoi(strings.TrimSpace(strings.TrimLeft(endPage, "-")))
+		if errStart == nil && errEnd == nil && start > end {
+			return &ErrReversedPages{Key: key, Value: value}
+		}
+	}
+	for _, pageRange := range strings.Split(stripBraces(value), ",") {
+		for _, page := range regexPageRangeSeparator.Split(strings.TrimSpace(pageRange), -1) {
+			if !regexPage.MatchString(page) {
+				return &ErrSuspiciousValue{Key: key, Field: "pages", Value: value, Expected: "a page or a page range"}
+			}
+		}
+	}
+	return nil
+}
+
+// Regex to split page ranges on hyphens (the style is checked by validatePages)
+var regexPageRangeSeparator = regexp.MustCompile(`\s*-+\s*`)
+
+// Regex to match a single page like 123, e12345, S12, 12a, 12+, or xii
+var regexPage = regexp.MustCompile(`^([A-Za-z]{0,2}\d+[a-z]?\+?|[ivxlcdm]+|[IVXLCDM]+)$`)
+
+// Regex to match a 4-digit year
+var regexFourDigitYear = regexp.MustCompile(`^\d{4}$`)
+
+// yearExceptions are the values of the year field which are no number, but common
+// for unpublished works (biblatex uses the pubstate field for them).
+var yearExceptions = map[string]bool{
+	"forthcoming": true, "in press": true, "n.d.": true, "submitted": true, "to appear": true,
+}
+
+// Regex to match a numeric volume or number like 12, 3/4, 1-2, or XII
+var regexNumeric = regexp.MustCompile(`^(\d+[a-z]?|[IVXLCDM]+)(\s*(/|-{1,2})\s*(\d+[a-z]?|[IVXLCDM]+))?$`)
+
+// validateYear checks if the value is a 4-digit year (e.g., 2024) or one of the yearExceptions
+// (e.g., forthcoming). Other values like "forthcoming 2024" are reported as an
+// ErrSuspiciousValue warning.
+func validateYear(key, value string) error {
+	year := strings.TrimSpace(stripBraces(value))
+	if !regexFourDigitYear.MatchString(year) && !yearExceptions[strings.ToLower(year)] {
+		return &ErrSuspiciousValue{Key: key, Field: "year", Value: value, Expected: "a 4-digit year"}
+	}
+	return nil
+}
+
+// validateVolume checks if the value is numeric (e.g., 12, 3/4, or XII) and reports other
+// values as an ErrSuspiciousValue warning.
+func validateVolume(key, value string) error {
+	if !regexNumeric.MatchString(strings.TrimSpace(stripBraces(value))) {
+		return &ErrSuspiciousValue{Key: key, Field: "volume", Value: value, Expected: "a number"}
+	}
+	return nil
+}
+
+// validateNumber checks if the value contains a number. As the number of a report can be
+// an identifier (e.g., TR-2024-01), only values without any digit or Roman numeral
+// (e.g., "special issue") are reported as an ErrSuspiciousValue warning.
+func validateNumber(key, value string) error {
+	number := strings.TrimSpace(stripBraces(value))
+	if !strings.ContainsAny(number, "0123456789") && !regexNumeric.MatchString(number) {
+		return &ErrSuspiciousValue{Key: key, Field: "number", Value: value, Expected: "a number"}
+	}
+	return nil
+}
+
+// validISBN10 checks the check digit of an ISBN-10 without hyphens.
+// The weighted sum of all digits (10 for the first to 1 for the last) must be divisible by 11.
+func validISBN10(isbn string) bool {
+	sum := 0
+	for i, char := range isbn {
+		var digit int
+		switch {
+		case char >= '0' && char <= '9':
+			digit = int(char - '0')
+		case (char == 'X' || char == 'x') && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * digit
+	}
+	return sum%11 == 0
+}
+
+// validISBN13 checks the check digit of an ISBN-13 without hyphens.
+// The sum of all digits, alternately weighted by 1 and 3, must be divisible by 10.
+func validISBN13(isbn string) bool {
+	sum := 0
+	for i, char := range isbn {
+		if char < '0' || char > '9' {
+			return false
+		}
+		digit := int(char - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// LaTeX escapes of characters that are common in URLs (e.g., \% in https://a.org/a\%20b)
+var urlUnescaper = strings.NewReplacer(`\%`, "%", `\_`, "_", `\#`, "#", `\&`, "&", `\~`, "~")
+
+// validateURL checks if the value is an absolute URL with a scheme and a host (e.g.,
+// https://example.org/path). Braces and LaTeX escapes like \% are ignored, and
+// percent-encoded characters must be valid escapes. URLs without a host are only
+// accepted for opaque schemes like mailto: and for file: URLs.
+func validateURL(key, value string) error {
+	u, err := url.Parse(urlUnescaper.Replace(strings.TrimSpace(stripBraces(value))))
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "" && u.Scheme != "file") {
+		return &ErrInvalidURL{Key: key, Value: value}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Unit-tests for fields.go
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestValidateDOI(t *testing.T) {
+	// Case 1: Valid DOIs
+	for _, doi := range []string{"10.1000/182", "10.1016/j.jair.2021.03.001", "10.1007/978-3-030-12345-6_5"} {
+		if err := validateDOI("id", doi); err != nil {
+			t.Errorf("Expected no error for '%s', but got '%#v'", doi, err)
+		}
+	}
+	// Case 2: Malformed DOIs
+	for _, doi := range []string{"", "10.10/182", "https://doi.org/10.1000/182", "10.1000", "10.1000/ 182"} {
+		expected := &ErrInvalidDOI{Key: "id", Value: doi}
+		err := validateDOI("id", doi)
+		if err == nil || expected.Error() != err.Error() {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, err)
+		}
+	}
+}
+
+func TestResolveDOI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/10.1000/182" {
+			w.Write([]byte(`{"responseCode":1,"handle":"10.1000/182"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"responseCode":100,"handle":"10.1000/unknown"}`))
+	}))
+	defer server.Close()
+	defaultAPI := doiHandleAPI
+	doiHandleAPI = server.URL + "/"
+	defer func() { doiHandleAPI = defaultAPI }()
+
+	// Case 1: Registered DOI
+	resolved, err := ResolveDOI(context.Background(), "10.1000/182")
+	if err != nil || !resolved {
+		t.Errorf("Expected DOI to resolve, but got '%v' (%v)", resolved, err)
+	}
+
+	// Case 2: Unknown DOI
+	resolved2, err2 := ResolveDOI(context.Background(), "10.1000/unknown")
+	if err2 != nil || resolved2 {
+		t.Errorf("Expected DOI not to resolve, but got '%v' (%v)", resolved2, err2)
+	}
+
+	// Case 3: Only checked by ValidateWithOptions if requested
+	entry, _ := ParseNewEntry(`@misc{doe2021, doi = {10.1000/unknown}}`)
+	if errs := entry.Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+	expected3 := &ErrUnresolvedDOI{Key: "doe2021", Value: "10.1000/unknown"}
+	errs3 := entry.ValidateWithOptions(context.Background(), ValidateOptions{ResolveDOIs: true})
+	if len(errs3) != 1 || expected3.Error() != errs3[0].Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, errs3)
+	}
+}
+
+func TestValidateISBN(t *testing.T) {
+	// Case 1: Valid ISBN-10 and ISBN-13
+	for _, isbn := range []string{"978-3-16-148410-0", "978-0201896831", "9780306406157", "0-201-89683-4", "0 8044 2957 X", "080442957x"} {
+		if err := validateISBN("id", isbn); err != nil {
+			t.Errorf("Expected no error for '%s', but got '%#v'", isbn, err)
+		}
+	}
+	// Case 2: Invalid check digits, lengths, and characters
+	for _, isbn := range []string{"978-3-16-148410-1", "0-201-89683-5", "X-201-89683-4", "978-3-16-14841", "", "978-3-16-148410-X"} {
+		expected := &ErrInvalidISBN{Key: "id", Value: isbn}
+		err := validateISBN("id", isbn)
+		if err == nil || expected.Error() != err.Error() {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, err)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	// Case 1: Valid URLs, including braces, percent-encoding, and LaTeX escapes
+	for _, u := range []string{
+		"https://www.technik-verlag.de/buecher/fortgeschrittene-datenanalyse", "{https://example.org/a%20b}",
+		`https://example.org/a\%20b\_c?q=1\&r=2\#top`, "http://example.org/a b", "ftp://ftp.example.org", "mailto:jane@example.org", "file:///tmp/a.pdf",
+	} {
+		if err := validateURL("id", u); err != nil {
+			t.Errorf("Expected no error for '%s', but got '%#v'", u, err)
+		}
+	}
+	// Case 2: URLs without a scheme or host and invalid percent-encoding
+	for _, u := range []string{"", "www.example.org", "/path/to/file", "https:/example.org", "https://", "https://example.org/100%", "https://example.org/%zz"} {
+		expected := &ErrInvalidURL{Key: "id", Value: u}
+		err := validateURL("id", u)
+		if err == nil || expected.Error() != err.Error() {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, err)
+		}
+	}
+}
+
+func TestValidatePages(t *testing.T) {
+	// Case 1: Valid pages
+	for _, pages := range []string{"123--145", "45--52", "7", "xii--xv", "e12345", "1--5, 7--9", "100---110"} {
+		if err := validatePages("id", pages); err != nil {
+			t.Errorf("Expected no error for '%s', but got '%#v'", pages, err)
+		}
+	}
+	// Case 2: Single hyphen is a warning
+	expected2 := &ErrPagesHyphen{Key: "id", Value: "123-145"}
+	err2 := validatePages("id", "123-145")
+	if err2 == nil || expected2.Error() != err2.Error() || !IsWarning(err2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, err2)
+	}
+	// Case 3: Reversed ranges are errors
+	expected3 := &ErrReversedPages{Key: "id", Value: "1--5, 145--123"}
+	err3 := validatePages("id", "1--5, 145--123")
+	if err3 == nil || expected3.Error() != err3.Error() || IsWarning(err3) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, err3)
+	}
+	// Case 4: Text that is no page is a warning
+	expected4 := &ErrSuspiciousValue{Key: "id", Field: "pages", Value: "pp. 12--15", Expected: "a page or a page range"}
+	err4 := validatePages("id", "pp. 12--15")
+	if !reflect.DeepEqual(expected4, err4) || !IsWarning(err4) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected4, err4)
+	}
+}
+
+func TestValidateSuspiciousValues(t *testing.T) {
+	validators := map[string]func(key, value string) error{"year": validateYear, "volume": validateVolume, "number": validateNumber}
+	// Case 1: Valid values
+	valid := map[string][]string{
+		"year":   {"2024", "{2024}", "forthcoming", "In Press", "n.d."},
+		"volume": {"12", "3/4", "1--2", "XII", "12a"},
+		"number": {"3", "3-4", "TR-2024-01", "S1", "IV"},
+	}
+	for field, values := range valid {
+		for _, value := range values {
+			if err := validators[field]("id", value); err != nil {
+				t.Errorf("Expected no error for %s '%s', but got '%#v'", field, value, err)
+			}
+		}
+	}
+	// Case 2: Suspicious values are warnings with the value
+	suspicious := map[string][]string{
+		"year":   {"{forthcoming 2024}", "24", "2024a", "ca. 1900"},
+		"volume": {"twelve", "12 (special issue)", "vol. 3"},
+		"number": {"special issue"},
+	}
+	for field, values := range suspicious {
+		for _, value := range values {
+			err := validators[field]("id", value)
+			var suspiciousErr *ErrSuspiciousValue
+			if !errors.As(err, &suspiciousErr) || suspiciousErr.Value != value || !IsWarning(err) {
+				t.Errorf("Expected an ErrSuspiciousValue for %s '%s', but got '%#v'", field, value, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// The format.go source file includes functions to write entries in a canonical BibTeX format
+//
+// String: serializes an Entry as normalized BibTeX
+// WriteTo: writes a BibTeXFile as normalized BibTeX
+// RawFieldName: returns the original casing of a field name
+// UnformattedKeys: reports the entries that are not in the canonical format
+// CheckWriteBack: reports the content of a BibTeXFile that WriteTo would lose
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Define errors
+type ErrLossyWrite struct {
+	Key    string // The key of the entry ("" for text between the entries).
+	Line   int    // The line of the content in the BibTeX file (0 if unknown).
+	Reason string // What would be lost, e.g., a % comment.
+}
+
+func (e *ErrLossyWrite) Error() string {
+	if e.Key != "" {
+		return fmt.Sprintf("Error writing BibTeX entry '%s' (line %d): %s", e.Key, e.Line, e.Reason)
+	}
+	return fmt.Sprintf("Error writing BibTeX file (line %d): %s", e.Line, e.Reason)
+}
+
+// FormatOptions control how entries are written by Format and WriteToWithOptions.
+type FormatOptions struct {
+	IndentWidth int  // The number of white spaces before each field.
+	AlignEquals bool // Pad field names so that all '=' of an entry are aligned.
+	KeepCase    bool // Write the field names as in the BibTeX file (see RawFieldName) instead of lowercased.
+	ASCII       bool // Convert accented and special letters of the values to LaTeX commands, see EncodeLaTeX.
+	DropMacros  bool // Omit the @string definitions in WriteToWithOptions and expand all macro references, see there.
+}
+
+// DefaultFormatOptions are used by String and WriteTo.
+var DefaultFormatOptions = FormatOptions{IndentWidth: 2}
+
+// String returns the entry in a normalized BibTeX format with one field per line:
+//
+//	@article{key,
+//	  author = {...},
+//	  title = {...}
+//	}
+//
+// The required fields of the entry type come first (see Validate), followed by all
+// other fields in alphabetical order. All values are wrapped in braces, and the names of
+// name lists like author are separated by a single " and " (see NormalizeNameList).
+// Values written with @string macros (e.g., month = jan or publisher = acm # " Press") are
+// written as in the BibTeX file, unless they were changed after parsing.
+// String uses the DefaultFormatOptions, see Format.
+func (e *Entry) String() string {
+	return e.Format(DefaultFormatOptions)
+}
+
+// Format returns the entry in a normalized BibTeX format like String, using the given options.
+func (e *Entry) Format(opts FormatOptions) string {
+	fieldNames := e.fieldOrder()
+	// Find longest field name to align '='
+	nameWidth := 0
+	if opts.AlignEquals {
+		for _, fieldName := range fieldNames {
+			nameWidth = max(nameWidth, len(fieldName))
+		}
+	}
+	indent := strings.Repeat(" ", max(opts.IndentWidth, 0))
+	var builder strings.Builder
+	builder.WriteString("@" + strings.ToLower(e.EntryType) + "{" + e.Key)
+	for _, fieldName := range fieldNames {
+		padding := strings.Repeat(" ", max(nameWidth-len(fieldName), 0))
+		writtenName := fieldName
+		if opts.KeepCase {
+			writtenName = e.RawFieldName(fieldName)
+		}
+		value := e.Fields[fieldName]
+		if macro, ok := e.macroValues[fieldName]; ok && macro.Value == value && !opts.DropMacros {
+			builder.WriteString(",\n" + indent + writtenName + padding + " = " + macro.Expression)
+			continue
+		}
+		if nameListFields[fieldName] {
+			value = NormalizeNameList(value)
+		}
+		if opts.ASCII {
+			value = EncodeLaTeX(value)
+		}
+		builder.WriteString(",\n" + indent + writtenName + padding + " = {" + value + "}")
+	}
+	builder.WriteString("\n}")
+	return builder.String()
+}
+
+// IsFormatted checks if the raw entry is already written in the format of Format with the
+// given options, ignoring white spaces around the entry.
+func (e *Entry) IsFormatted(opts FormatOptions) bool {
+	return strings.TrimSpace(e.RawEntry) == e.Format(opts)
+}
+
+// UnformattedKeys returns the keys of all entries that are not in the format of Format with
+// the given options (see IsFormatted), in the order of Entries. Nothing is rewritten, so it
+// can be used to check the formatting of BibTeX files, e.g., in CI.
+func (f *BibTeXFile) UnformattedKeys(opts FormatOptions) []string {
+	var keys []string
+	for _, entry := range f.Entries {
+		if !entry.IsFormatted(opts) {
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys
+}
+
+// RawFieldName returns the field name as written in the BibTeX file for the given
+// lowercased field name (e.g., SERIES for series). If the original name is not known
+// (e.g., for fields added after parsing), the given name is returned.
+func (e *Entry) RawFieldName(normalized string) string {
+	if rawFieldName, ok := e.RawFieldNames[normalized]; ok {
+		return rawFieldName
+	}
+	return normalized
+}
+
+// WriteTo writes the whole BibTeX file in a normalized format to w, using the DefaultFormatOptions.
+// It implements io.WriterTo, see WriteToWithOptions.
+func (f *BibTeXFile) WriteTo(w io.Writer) (int64, error) {
+	return f.WriteToWithOptions(w, DefaultFormatOptions)
+}
+
+// WriteToWithOptions writes the whole BibTeX file in a normalized format to w.
+// The @preamble comes first, followed by the @string macros in alphabetical order and
+// all entries (see Format), separated by blank lines. The @comment blocks (including
+// disabled entries) keep their position among the entries; comments added after parsing
+// are written before the first entry.
+// The output is deterministic, so it can be used to reformat BibTeX files canonically.
+// It returns the number of bytes written.
+//
+// The macro references of the values are kept (see Format), so changing a @string definition
+// still changes the values of the entries. With opts.DropMacros, the definitions are
+// omitted and all references are expanded, which gives a self-contained file without macros.
+// Text that cannot be written back, e.g., % comments between the entries, is lost; use
+// CheckWriteBack before overwriting the BibTeX file.
+func (f *BibTeXFile) WriteToWithOptions(w io.Writer, opts FormatOptions) (int64, error) {
+	var written int64
+	// writeBlock writes a block of text, separated by a blank line from the previous one
+	writeBlock := func(block string) error {
+		if written > 0 {
+			block = "\n" + block
+		}
+		n, err := io.WriteString(w, block+"\n")
+		written += int64(n)
+		return err
+	}
+	// Write @preamble
+	if f.Preamble != "" {
+		if err := writeBlock("@preamble{" + f.Preamble + "}"); err != nil {
+			return written, err
+		}
+	}
+	// Write @string macros
+	if len(f.Macros) > 0 && !opts.DropMacros {
+		names := make([]string, 0, len(f.Macros))
+		for name := range f.Macros {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		definitions := make([]string, 0, len(names))
+		for _, name := range names {
+			definitions = append(definitions, "@string{"+name+" = {"+f.Macros[name]+"}}")
+		}
+		if err := writeBlock(strings.Join(definitions, "\n")); err != nil {
+			return written, err
+		}
+	}
+	// Write entries, each after the @comment blocks before it
+	comments := f.commentsBefore()
+	for i, entry := range f.Entries {
+		for _, comment := range comments[i] {
+			if err := writeBlock("@comment{" + comment + "}"); err != nil {
+				return written, err
+			}
+		}
+		if err := writeBlock(entry.Format(opts)); err != nil {
+			return written, err
+		}
+	}
+	for _, comment := range comments[len(f.Entries)] {
+		if err := writeBlock("@comment{" + comment + "}"); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// commentsBefore returns the Comments grouped by the index of the entry they precede in
+// the BibTeX file (len(Entries) for the comments after the last entry). Comments without
+// a recorded position (e.g., added after parsing or by Merge) precede the first entry.
+func (f *BibTeXFile) commentsBefore() map[int][]string {
+	comments := make(map[int][]string)
+	for i, comment := range f.Comments {
+		index := 0
+		if i < len(f.commentIndices) {
+			index = min(f.commentIndices[i], len(f.Entries))
+		}
+		comments[index] = append(comments[index], comment)
+	}
+	return comments
+}
+
+// CheckWriteBack returns one ErrLossyWrite per content of the file that would be lost or
+// silently changed when the file is written with WriteTo: the stray text and % comments
+// between the entries, the parse warnings of entries (e.g., a missing ','), all but the
+// last value of duplicate fields, and field names that are no valid BibTeX names (e.g.,
+// "my field"). The closing braces added on request (see ParseOptions.RepairMissingBrace)
+// and the parsing errors of the file (see Errors) are not reported.
+func (f *BibTeXFile) CheckWriteBack() []error {
+	var errs []error
+	for _, stray := range f.StrayText {
+		errs = append(errs, &ErrLossyWrite{Line: stray.Line, Reason: fmt.Sprintf("the text outside of entries would be lost: %s", truncateRunes(strings.Join(strings.Fields(stray.Text), " "), 40))})
+	}
+	for _, comment := range f.TeXComments {
+		errs = append(errs, &ErrLossyWrite{Line: comment.Line, Reason: fmt.Sprintf("the %% comment would be lost: %s", truncateRunes(comment.Text, 40))})
+	}
+	for _, entry := range f.Entries {
+		for _, warning := range entry.ParseWarnings {
+			if _, ok := warning.(*ErrMissingClosingBrace); ok {
+				continue
+			}
+			errs = append(errs, &ErrLossyWrite{Key: entry.Key, Line: entry.Line, Reason: fmt.Sprintf("the entry would be rewritten despite a syntax problem (%s)", warning)})
+		}
+		for _, fieldName := range entry.DuplicateFields {
+			errs = append(errs, &ErrLossyWrite{Key: entry.Key, Line: entry.Line, Reason: fmt.Sprintf("all but the last value of field '%s' would be lost", fieldName)})
+		}
+		for _, fieldName := range entry.fieldOrder() {
+			if rawFieldName := entry.RawFieldName(fieldName); !regexFieldName.MatchString(rawFieldName) {
+				errs = append(errs, &ErrLossyWrite{Key: entry.Key, Line: entry.Line, Reason: fmt.Sprintf("the invalid field name '%s' would be written as a field", rawFieldName)})
+			}
+		}
+	}
+	return errs
+}
+
+// fieldOrder returns the field names of the entry in a stable order. The required fields of
+// the entry type come first, followed by all other fields in alphabetical order.
+func (e *Entry) fieldOrder() []string {
+	order := make([]string, 0, len(e.Fields))
+	seen := make(map[string]bool, len(e.Fields))
+	for _, rule := range fieldRules[EntryType(strings.ToLower(e.EntryType))] {
+		if rule.Optional {
+			continue
+		}
+		for _, fieldName := range rule.Fields {
+			if _, ok := e.Fields[fieldName]; ok && !seen[fieldName] {
+				order = append(order, fieldName)
+				seen[fieldName] = true
+			}
+		}
+	}
+	var others []string
+	for fieldName := range e.Fields {
+		if !seen[fieldName] {
+			others = append(others, fieldName)
+		}
+	}
+	sort.Strings(others)
+	return append(order, others...)
+}
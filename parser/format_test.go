@@ -0,0 +1,259 @@
+// Unit-tests for format.go
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEntryString(t *testing.T) {
+	// Case 1: Required fields first, then alphabetical order
+	entry1, _ := ParseNewEntry(`@Article{smith2021ai,
+  doi          = {10.1016/j.jair.2021.03.001},
+  year         = 2021,
+  pages        = "123--145",
+  title        = {Advancements in {AI} for Natural Language Processing},
+  journal      = {Journal of Artificial Intelligence Research},
+  author       = {John Smith and Alice Johnson}
+}`)
+	expected1 := `@article{smith2021ai,
+  author = {John Smith and Alice Johnson},
+  title = {Advancements in {AI} for Natural Language Processing},
+  journal = {Journal of Artificial Intelligence Research},
+  year = {2021},
+  doi = {10.1016/j.jair.2021.03.001},
+  pages = {123--145}
+}`
+	if expected1 != entry1.String() {
+		t.Errorf("Expected '%s', but got '%s'", expected1, entry1.String())
+	}
+
+	// Case 2: String output can be parsed again
+	entry2, err := ParseNewEntry(entry1.String())
+	if err != nil || expected1 != entry2.String() {
+		t.Errorf("Expected '%s', but got '%s' (%v)", expected1, entry2.String(), err)
+	}
+
+	// Case 3: Unknown entry type and no fields
+	entry3 := &Entry{EntryType: "blogpost", Key: "doe2021"}
+	expected3 := "@blogpost{doe2021\n}"
+	if expected3 != entry3.String() {
+		t.Errorf("Expected '%s', but got '%s'", expected3, entry3.String())
+	}
+
+	// Case 4: ASCII-safe output
+	entry4 := &Entry{EntryType: "misc", Key: "mueller2020", Fields: map[string]string{"author": "Jürgen Müller"}}
+	expected4 := "@misc{mueller2020,\n  author = {J{\\\"u}rgen M{\\\"u}ller}\n}"
+	if result4 := entry4.Format(FormatOptions{IndentWidth: 2, ASCII: true}); expected4 != result4 {
+		t.Errorf("Expected '%s', but got '%s'", expected4, result4)
+	}
+}
+
+func TestRawFieldName(t *testing.T) {
+	entry, _ := ParseNewEntry(`@book{muster2024, Title = {Buch}, SERIES = {Reihe}, year = {2024}}`)
+	// Case 1: Original casing is kept, lookups use the lowercased names
+	if entry.RawFieldName("series") != "SERIES" || entry.RawFieldName("title") != "Title" || entry.Fields["series"] != "Reihe" {
+		t.Errorf("Expected '%#v', but got '%#v'", map[string]string{"series": "SERIES", "title": "Title", "year": "year"}, entry.RawFieldNames)
+	}
+	// Case 2: Unknown field names are returned unchanged
+	if entry.RawFieldName("note") != "note" {
+		t.Errorf("Expected '%s', but got '%s'", "note", entry.RawFieldName("note"))
+	}
+	// Case 3: Format keeps the casing with KeepCase
+	expected3a := "@book{muster2024,\n  title = {Buch},\n  year = {2024},\n  series = {Reihe}\n}"
+	if result3a := entry.Format(FormatOptions{IndentWidth: 2}); expected3a != result3a {
+		t.Errorf("Expected '%s', but got '%s'", expected3a, result3a)
+	}
+	expected3b := "@book{muster2024,\n  Title = {Buch},\n  year = {2024},\n  SERIES = {Reihe}\n}"
+	if result3b := entry.Format(FormatOptions{IndentWidth: 2, KeepCase: true}); expected3b != result3b {
+		t.Errorf("Expected '%s', but got '%s'", expected3b, result3b)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	bib := `@string{pub = {ACM Press}}
+@comment{Exported}
+@preamble{"\noopsort"}
+@book{knuth1997art,
+  title        = {The Art of Computer Programming},
+  author       = {Donald E. Knuth},
+  year         = {1997},
+  publisher    = pub
+}
+@misc{doe2021, title = {Misc}, howpublished = {Online}}
+`
+	parsedBibTeXFile, _ := ParseNewBibTeXFile(strings.NewReader(bib))
+
+	// Case 1: Default options, the macro references are kept
+	expected1 := `@preamble{"\noopsort"}
+
+@string{pub = {ACM Press}}
+
+@comment{Exported}
+
+@book{knuth1997art,
+  author = {Donald E. Knuth},
+  title = {The Art of Computer Programming},
+  publisher = pub,
+  year = {1997}
+}
+
+@misc{doe2021,
+  howpublished = {Online},
+  title = {Misc}
+}
+`
+	var builder1 strings.Builder
+	n1, err1 := parsedBibTeXFile.WriteTo(&builder1)
+	if err1 != nil || expected1 != builder1.String() {
+		t.Errorf("Expected '%s', but got '%s' (%v)", expected1, builder1.String(), err1)
+	}
+	if n1 != int64(len(expected1)) {
+		t.Errorf("Expected '%d', but got '%d'", len(expected1), n1)
+	}
+
+	// Case 2: Indentation width and aligned '='
+	expected2 := `@preamble{"\noopsort"}
+
+@string{pub = {ACM Press}}
+
+@comment{Exported}
+
+@book{knuth1997art,
+    author    = {Donald E. Knuth},
+    title     = {The Art of Computer Programming},
+    publisher = pub,
+    year      = {1997}
+}
+
+@misc{doe2021,
+    howpublished = {Online},
+    title        = {Misc}
+}
+`
+	var builder2 strings.Builder
+	_, err2 := parsedBibTeXFile.WriteToWithOptions(&builder2, FormatOptions{IndentWidth: 4, AlignEquals: true})
+	if err2 != nil || expected2 != builder2.String() {
+		t.Errorf("Expected '%s', but got '%s' (%v)", expected2, builder2.String(), err2)
+	}
+
+	// Case 3: Output is deterministic and can be parsed again
+	reparsedBibTeXFile, _ := ParseNewBibTeXFile(strings.NewReader(builder1.String()))
+	var builder3 strings.Builder
+	_, _ = reparsedBibTeXFile.WriteTo(&builder3)
+	if builder1.String() != builder3.String() {
+		t.Errorf("Expected '%s', but got '%s'", builder1.String(), builder3.String())
+	}
+
+	// Case 4: The @string definitions are dropped and the values are expanded
+	expected4 := strings.Replace(strings.Replace(expected1, "@string{pub = {ACM Press}}\n\n", "", 1), "publisher = pub", "publisher = {ACM Press}", 1)
+	var builder4 strings.Builder
+	_, err4 := parsedBibTeXFile.WriteToWithOptions(&builder4, FormatOptions{IndentWidth: 2, DropMacros: true})
+	if err4 != nil || expected4 != builder4.String() {
+		t.Errorf("Expected '%s', but got '%s' (%v)", expected4, builder4.String(), err4)
+	}
+	if strings.Contains(builder4.String(), "@string") || !strings.Contains(builder4.String(), "publisher = {ACM Press}") {
+		t.Errorf("Expected no macros, but got '%s'", builder4.String())
+	}
+
+	// Case 5: Concatenations and month macros are kept, changed values are expanded
+	entry5, _ := ParseNewEntry(`@misc{a, month = jan, note = "Vol. " # 2 # {, Preprint}, year = 2024}`)
+	expected5 := "@misc{a,\n  month = jan,\n  note = \"Vol. \" # 2 # {, Preprint},\n  year = {2024}\n}"
+	if result5 := entry5.String(); expected5 != result5 {
+		t.Errorf("Expected '%s', but got '%s'", expected5, result5)
+	}
+	entry5.SetField("month", "February")
+	expected5b := "@misc{a,\n  month = {February},\n  note = \"Vol. \" # 2 # {, Preprint},\n  year = {2024}\n}"
+	if result5b := entry5.Clone().String(); expected5b != result5b {
+		t.Errorf("Expected '%s', but got '%s'", expected5b, result5b)
+	}
+}
+
+func TestWriteToCommentPositions(t *testing.T) {
+	bib := `@misc{a, title = {A}}
+@comment{@misc{b, title = {B}}}
+@misc{c, title = {C}}
+@comment{End of file}
+`
+	file, _ := ParseString(bib)
+
+	// Case 1: The @comment blocks and disabled entries stay between the same entries
+	expected1 := `@misc{a,
+  title = {A}
+}
+
+@comment{@misc{b, title = {B}}}
+
+@misc{c,
+  title = {C}
+}
+
+@comment{End of file}
+`
+	var builder1 strings.Builder
+	if _, err := file.Clone().WriteTo(&builder1); err != nil || expected1 != builder1.String() {
+		t.Errorf("Expected '%s', but got '%s' (%v)", expected1, builder1.String(), err)
+	}
+
+	// Case 2: Comments added after parsing are written before the first entry
+	file.Comments = append(file.Comments, "Added")
+	var builder2 strings.Builder
+	_, _ = file.WriteTo(&builder2)
+	if !strings.HasPrefix(builder2.String(), "@comment{Added}\n\n@misc{a,") {
+		t.Errorf("Expected the added comment first, but got '%s'", builder2.String())
+	}
+}
+
+func TestCheckWriteBack(t *testing.T) {
+	bib := `% Exported by my reference manager
+@misc{a, title = {A} year = {2024}}
+trailing text
+@misc{b, title = {B1}, title = {B2}, my field = {x}}
+@misc{c, month = jan, date-added = {2024-01-01}}
+`
+	file, _ := ParseNewBibTeXFile(strings.NewReader(bib))
+
+	// Case 1: Everything that WriteTo would lose is reported with its line
+	expected1 := []error{
+		&ErrLossyWrite{Line: 3, Reason: "the text outside of entries would be lost: trailing text"},
+		&ErrLossyWrite{Line: 1, Reason: "the % comment would be lost: % Exported by my reference manager"},
+		&ErrLossyWrite{Key: "a", Line: 2, Reason: "the entry would be rewritten despite a syntax problem (Warning parsing BibTeX entry 'a': missing ',' before field 'year')"},
+		&ErrLossyWrite{Key: "b", Line: 4, Reason: "all but the last value of field 'title' would be lost"},
+		&ErrLossyWrite{Key: "b", Line: 4, Reason: "the invalid field name 'my field' would be written as a field"},
+	}
+	if errs1 := file.CheckWriteBack(); !reflect.DeepEqual(expected1, errs1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, errs1)
+	}
+
+	// Case 2: Files that can be written back unchanged
+	clean, _ := ParseNewBibTeXFile(strings.NewReader("@string{pub = {P}}\n@misc{c, month = jan, publisher = pub}\n"))
+	if errs2 := clean.CheckWriteBack(); len(errs2) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs2)
+	}
+}
+
+func TestUnformattedKeys(t *testing.T) {
+	bib := `@misc{formatted,
+  title = {Formatted}
+}
+
+@misc{unformatted, title={Unformatted}}
+
+@MISC{uppercase,
+  title = {Uppercase}
+}
+`
+	file, _ := ParseNewBibTeXFile(strings.NewReader(bib))
+
+	// Case 1: Only the canonically formatted entry is not reported
+	expected1 := []string{"unformatted", "uppercase"}
+	if keys := file.UnformattedKeys(DefaultFormatOptions); !reflect.DeepEqual(expected1, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, keys)
+	}
+
+	// Case 2: The entries are not changed
+	if entry, _ := file.LookupByKey("unformatted"); entry.RawEntry != "@misc{unformatted, title={Unformatted}}" {
+		t.Errorf("Expected '%#v', but got '%#v'", "@misc{unformatted, title={Unformatted}}", entry.RawEntry)
+	}
+}
@@ -0,0 +1,38 @@
+// The json.go source file includes functions to export a BibTeXFile as JSON
+//
+// MarshalJSON: encodes all entries of a BibTeXFile as one JSON object
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import "encoding/json"
+
+// jsonEntry is the JSON representation of an Entry used by MarshalJSON.
+type jsonEntry struct {
+	Type   string            `json:"type"`
+	Fields map[string]string `json:"fields"`
+}
+
+// MarshalJSON encodes the entries as one JSON object keyed by the BibTeX keys, e.g.:
+//
+//	{"knuth1997":{"type":"book","fields":{"title":"The Art of Computer Programming"}}}
+//
+// The entries and their fields are sorted by key and field name, so the output is stable
+// and can be diffed. If several entries share the same key, the first one is used
+// (see LookupByKey). It implements json.Marshaler.
+func (f *BibTeXFile) MarshalJSON() ([]byte, error) {
+	entries := make(map[string]jsonEntry, len(f.Entries))
+	for _, entry := range f.Entries {
+		if _, ok := entries[entry.Key]; ok {
+			continue
+		}
+		fields := entry.Fields
+		if fields == nil {
+			fields = map[string]string{}
+		}
+		entries[entry.Key] = jsonEntry{Type: entry.EntryType, Fields: fields}
+	}
+	// Maps are encoded with sorted keys
+	return json.Marshal(entries)
+}
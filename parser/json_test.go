@@ -0,0 +1,35 @@
+// Unit-tests for json.go
+package parser
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	// Case 1: Entries keyed by their key with sorted fields
+	bibtexFile, _ := ParseNewBibTeXFile(strings.NewReader(`@book{knuth1997, year = 1997, title = {The Art of Computer Programming}, author = {Donald Knuth}}
+@misc{doe2021, title = {Valid}}
+@misc{doe2021, title = {Duplicate}}`))
+	expected1 := `{"doe2021":{"type":"misc","fields":{"title":"Valid"}},"knuth1997":{"type":"book","fields":{"author":"Donald Knuth","title":"The Art of Computer Programming","year":"1997"}}}`
+	data1, err1 := bibtexFile.MarshalJSON()
+	if err1 != nil {
+		t.Errorf("Expected no error, but got '%#v'", err1)
+	}
+	if string(data1) != expected1 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, string(data1))
+	}
+
+	// Case 2: json.Marshal uses MarshalJSON
+	data2, _ := json.Marshal(bibtexFile)
+	if string(data2) != expected1 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, string(data2))
+	}
+
+	// Case 3: Empty file
+	data3, _ := (&BibTeXFile{}).MarshalJSON()
+	if string(data3) != "{}" {
+		t.Errorf("Expected '%#v', but got '%#v'", "{}", string(data3))
+	}
+}
@@ -0,0 +1,181 @@
+// The keys.go source file includes functions to generate keys for BibTeX entries
+//
+// SuggestKey: generates a key from the last name of the first author and the year
+// SuggestUniqueKey: generates a key that is not used by another entry of a BibTeXFile
+// NormalizeKeys: changes the keys of all entries of a BibTeXFile to a KeyStyle
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Define errors
+type ErrKeyCollision struct {
+	Key       string // The key of the entry before NormalizeKeys.
+	StyledKey string // The key of the style, which is already used by another entry.
+	NewKey    string // The key with a suffix the entry got instead.
+}
+
+func (e *ErrKeyCollision) Error() string {
+	return fmt.Sprintf("Warning normalizing BibTeX entry '%s': key '%s' is already used, renamed to '%s' instead", e.Key, e.StyledKey, e.NewKey)
+}
+
+// The name part of suggested keys of entries without authors and editors
+const anonymousKey = "anon"
+
+// SuggestKey returns a conventional key for the entry from the last name of its first
+// author (or editor) and its year (see Year), e.g., "schmidt2024" for an entry by
+// "Schmidt, Anna" from 2024, to replace missing or placeholder keys. The name is lowercased
+// without LaTeX commands, diacritics, spaces, and other chars that are not ASCII letters or
+// digits (e.g., "vanderberg" for "van der Berg, Jan" or "muller" for "M{\"u}ller, Max").
+// Entries without authors and editors get the name "anon", entries without a year no year.
+// The key may already be used by another entry, see BibTeXFile.SuggestUniqueKey.
+func (e *Entry) SuggestKey() string {
+	name := anonymousKey
+	for _, fieldName := range []string{"author", "editor"} {
+		names := SplitNames(e.Fields[fieldName])
+		if len(names) == 0 {
+			continue
+		}
+		_, von, last, _ := ParseName(names[0])
+		if keyName := keyLetters(von + last); keyName != "" {
+			name = keyName
+		}
+		break
+	}
+	if year, ok := e.Year(); ok {
+		return name + strconv.Itoa(year)
+	}
+	return name
+}
+
+// keyLetters returns the ASCII letters and digits of the decoded and folded name, see
+// SuggestKey.
+func keyLetters(name string) string {
+	var builder strings.Builder
+	for _, char := range foldASCII(DecodeLaTeX(stripBraces(name))) {
+		if char >= 'a' && char <= 'z' || char >= '0' && char <= '9' {
+			builder.WriteRune(char)
+		}
+	}
+	return builder.String()
+}
+
+// SuggestUniqueKey returns the key suggested for the entry (see Entry.SuggestKey) if no
+// other entry of the file uses it, or the key with the first free suffix of a, b, ..., z,
+// aa, ab, etc. otherwise, e.g., "schmidt2024a" if "schmidt2024" is taken. An entry that
+// already has the suggested key keeps it. The entry does not have to be part of the file,
+// e.g., to choose the key of an imported entry before adding it. The key of the entry is not
+// changed, see RenameKey.
+func (f *BibTeXFile) SuggestUniqueKey(e *Entry) string {
+	key := e.SuggestKey()
+	return uniqueKey(key, func(candidate string) bool {
+		other, ok := f.LookupByKey(candidate)
+		return ok && other != e
+	})
+}
+
+// uniqueKey returns key, or key with the first suffix (see keySuffix) for which taken
+// returns false.
+func uniqueKey(key string, taken func(candidate string) bool) string {
+	candidate := key
+	for i := 0; taken(candidate); i++ {
+		candidate = key + keySuffix(i)
+	}
+	return candidate
+}
+
+// keySuffix returns the i-th disambiguation suffix of keys: a, b, ..., z, aa, ab, etc.
+func keySuffix(i int) string {
+	var suffix []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		suffix = append([]byte{byte('a' + (i-1)%26)}, suffix...)
+	}
+	return string(suffix)
+}
+
+// KeyStyle returns the key of an entry in a key convention, see NormalizeKeys. It may return
+// "" to keep the key of the entry.
+type KeyStyle func(e *Entry) string
+
+// LowercaseKey is the KeyStyle of lowercased keys, e.g., doe2024 for Doe2024.
+func LowercaseKey(e *Entry) string {
+	return strings.ToLower(e.Key)
+}
+
+// AuthorYearKey is the KeyStyle of keys made of the last name of the first author and the
+// year, e.g., schmidt2024, see Entry.SuggestKey.
+func AuthorYearKey(e *Entry) string {
+	return e.SuggestKey()
+}
+
+// NormalizeKeys changes the keys of all entries to the key of the style, e.g., LowercaseKey,
+// AuthorYearKey, or a custom KeyStyle, to enforce a key convention across a file. The
+// crossref, xref, entryset, xdata, and related fields referencing the old keys are changed
+// as well (see RenameKey), but not the citations in LaTeX documents.
+// Entries that already have the key of the style keep it. If the key of the style is used
+// by another entry, the entry gets the key with the first free suffix instead (e.g.,
+// schmidt2024a, see SuggestUniqueKey), which is reported as an ErrKeyCollision warning.
+// Entries for which the style returns "" keep their key, and keys with invalid chars are
+// reported as ErrInvalidKey without changing the key of the entry.
+func (f *BibTeXFile) NormalizeKeys(style KeyStyle) []error {
+	var errs []error
+	styledKeys := make([]string, len(f.Entries))
+	taken := make(map[string]bool, len(f.Entries))
+	// Entries that already follow the style keep their keys, also if they come later
+	for i, entry := range f.Entries {
+		styledKey := style(entry)
+		if styledKey == "" {
+			styledKey = entry.Key
+		} else if err := validateKey(styledKey); err != nil {
+			errs = append(errs, err)
+			styledKey = entry.Key
+		}
+		styledKeys[i] = styledKey
+		if styledKey == entry.Key && !taken[styledKey] {
+			taken[styledKey] = true
+			styledKeys[i] = ""
+		}
+	}
+	// The references belong to the first entry with a key, see LookupByKey
+	first := make(map[string]int, len(f.Entries))
+	for i := len(f.Entries) - 1; i >= 0; i-- {
+		first[f.Entries[i].Key] = i
+	}
+	newKeys := make([]string, len(f.Entries))
+	for i, entry := range f.Entries {
+		styledKey := styledKeys[i]
+		if styledKey == "" {
+			continue
+		}
+		newKeys[i] = uniqueKey(styledKey, func(candidate string) bool { return taken[candidate] })
+		taken[newKeys[i]] = true
+		if newKeys[i] != styledKey {
+			errs = append(errs, &ErrKeyCollision{Key: entry.Key, StyledKey: styledKey, NewKey: newKeys[i]})
+		}
+	}
+	// References are changed via placeholders, so keys can be swapped (e.g., a to b and b
+	// to a) without changing a reference twice. The placeholders contain null chars, which
+	// are not allowed in keys.
+	for i, entry := range f.Entries {
+		if newKeys[i] != "" && entry.Key != "" && first[entry.Key] == i {
+			f.replaceKeyReferences(entry.Key, fmt.Sprintf("\x00%d", i))
+		}
+	}
+	for i, entry := range f.Entries {
+		if newKeys[i] == "" {
+			continue
+		}
+		if entry.Key != "" && first[entry.Key] == i {
+			f.replaceKeyReferences(fmt.Sprintf("\x00%d", i), newKeys[i])
+		}
+		entry.Key = newKeys[i]
+	}
+	f.keyIndex = nil
+	return errs
+}
@@ -0,0 +1,124 @@
+// Unit-tests for keys.go
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSuggestKey(t *testing.T) {
+	// Case 1: Last name of the first author or editor and the year
+	cases := map[string]string{
+		`@article{x, author = {Schmidt, Anna and Doe, Jane}, year = {2024}}`:         "schmidt2024",
+		`@book{x, editor = {Jane Doe}, date = {2021-05-03}}`:                         "doe2021",
+		`@misc{x, author = {M{\"u}ller, Max}, year = {1999}}`:                        "muller1999",
+		`@misc{x, author = {van der Berg, Jan}, year = {2010}}`:                      "vanderberg2010",
+		`@misc{x, author = {{World Health Organization}}, year = {2020}}`:            "worldhealthorganization2020",
+		`@misc{x, author = {García Márquez, Gabriel}, editor = {Doe, Jane}}`:         "garciamarquez",
+		`@misc{x, title = {Anonymous}, year = {2003}}`:                               "anon2003",
+		`@misc{x, title = {Neither names nor year}}`:                                 "anon",
+		`@misc{x, author = {O'Brien, Pat and others}, year = {Forthcoming in 2025}}`: "obrien2025",
+	}
+	for bib, expected := range cases {
+		entry, err := ParseNewEntry(bib)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key := entry.SuggestKey(); key != expected {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, key)
+		}
+	}
+}
+
+func TestSuggestUniqueKey(t *testing.T) {
+	bibtexFile, _ := ParseString(`@article{schmidt2024, author = {Schmidt, Anna}, year = {2024}}
+@article{schmidt2024a, author = {Schmidt, Bert}, year = {2024}}
+@article{placeholder, author = {Schmidt, Carl}, year = {2024}}`)
+
+	// Case 1: The first free suffix for an entry of the file
+	if key := bibtexFile.SuggestUniqueKey(bibtexFile.Entries[2]); key != "schmidt2024b" {
+		t.Errorf("Expected '%#v', but got '%#v'", "schmidt2024b", key)
+	}
+
+	// Case 2: An entry that already has the suggested key keeps it
+	if key := bibtexFile.SuggestUniqueKey(bibtexFile.Entries[0]); key != "schmidt2024" {
+		t.Errorf("Expected '%#v', but got '%#v'", "schmidt2024", key)
+	}
+
+	// Case 3: An entry that is not part of the file
+	entry, _ := ParseNewEntry(`@misc{new, author = {Doe, Jane}, year = {2024}}`)
+	if key := bibtexFile.SuggestUniqueKey(entry); key != "doe2024" {
+		t.Errorf("Expected '%#v', but got '%#v'", "doe2024", key)
+	}
+
+	// Case 4: The suffixes after z
+	expected := map[int]string{0: "a", 25: "z", 26: "aa", 27: "ab", 51: "az", 52: "ba", 701: "zz", 702: "aaa"}
+	for i, suffix := range expected {
+		if keySuffix(i) != suffix {
+			t.Errorf("Expected '%#v', but got '%#v'", suffix, keySuffix(i))
+		}
+	}
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	// Case 1: Lowercased keys with disambiguated collisions and changed references
+	bibtexFile, _ := ParseString(`@proceedings{Conf2024, title = {Conference}, year = {2024}}
+@inproceedings{Doe2024, author = {Doe, Jane}, title = {A}, crossref = {Conf2024}}
+@inproceedings{doe2024, author = {Doe, John}, title = {B}, crossref = {Conf2024}}
+@set{Set, entryset = {Doe2024, doe2024}}`)
+	errs := bibtexFile.NormalizeKeys(LowercaseKey)
+	expected := []error{&ErrKeyCollision{Key: "Doe2024", StyledKey: "doe2024", NewKey: "doe2024a"}}
+	if !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+	if len(errs) > 0 && !IsWarning(errs[0]) {
+		t.Errorf("Expected '%#v' to be a warning", errs[0])
+	}
+	expectedKeys := []string{"conf2024", "doe2024a", "doe2024", "set"}
+	if keys := entryKeys(bibtexFile.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+	entry, ok := bibtexFile.LookupByKey("doe2024a")
+	if !ok || entry.Fields["crossref"] != "conf2024" || bibtexFile.Entries[3].Fields["entryset"] != "doe2024a, doe2024" {
+		t.Errorf("Expected the references to be changed, but got '%#v'", bibtexFile.Entries)
+	}
+
+	// Case 2: Author-year keys for placeholder keys, also if the keys are swapped
+	bibtexFile2, _ := ParseString(`@book{doe2020, author = {Roe, Richard}, title = {A}, year = {2021}, related = {roe2021}}
+@book{roe2021, author = {Doe, Jane}, title = {B}, year = {2020}, related = {doe2020}}
+@book{ref1, author = {Doe, Jane}, title = {C}, year = {2020}, xref = {roe2021}}
+@book{, author = {Smith, Ann}, title = {D}, year = {2019}, crossref = {}}`)
+	if errs2 := bibtexFile2.NormalizeKeys(AuthorYearKey); len(errs2) != 1 {
+		t.Errorf("Expected a collision, but got '%#v'", errs2)
+	}
+	expectedKeys2 := []string{"roe2021", "doe2020", "doe2020a", "smith2019"}
+	if keys := entryKeys(bibtexFile2.Entries); !reflect.DeepEqual(expectedKeys2, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys2, keys)
+	}
+	references := []string{bibtexFile2.Entries[0].Fields["related"], bibtexFile2.Entries[1].Fields["related"], bibtexFile2.Entries[2].Fields["xref"], bibtexFile2.Entries[3].Fields["crossref"]}
+	if expected := []string{"doe2020", "roe2021", "doe2020", ""}; !reflect.DeepEqual(expected, references) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, references)
+	}
+
+	// Case 3: Custom styles, empty keys keep the key, invalid keys are reported
+	bibtexFile3, _ := ParseString(`@misc{a, title = {A}}
+@misc{b, title = {B}}
+@misc{c, title = {C}}`)
+	errs3 := bibtexFile3.NormalizeKeys(func(e *Entry) string {
+		switch e.Key {
+		case "a":
+			return "prefix:" + e.Key
+		case "b":
+			return "b b"
+		}
+		return ""
+	})
+	expected3 := []error{&ErrInvalidKey{Key: "b b", BadChar: ' '}}
+	if !reflect.DeepEqual(expected3, errs3) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, errs3)
+	}
+	if keys := strings.Join(entryKeys(bibtexFile3.Entries), " "); keys != "prefix:a b c" {
+		t.Errorf("Expected '%#v', but got '%#v'", "prefix:a b c", keys)
+	}
+}
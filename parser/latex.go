@@ -0,0 +1,234 @@
+// The latex.go source file includes functions to convert LaTeX commands in field values
+//
+// DecodeLaTeX: converts LaTeX accent commands and special letters to Unicode
+// EncodeLaTeX: converts accented and special letters to LaTeX commands
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// latexAccents maps the LaTeX accent commands to the Unicode combining characters.
+var latexAccents = map[string]rune{
+	`"`: '\u0308', // Diaeresis, e.g., \"o for ö
+	`'`: '\u0301', // Acute, e.g., \'e for é
+	"`": '\u0300', // Grave, e.g., \`a for à
+	"^": '\u0302', // Circumflex, e.g., \^o for ô
+	"~": '\u0303', // Tilde, e.g., \~n for ñ
+	"=": '\u0304', // Macron, e.g., \=a for ā
+	".": '\u0307', // Dot above, e.g., \.z for ż
+	"b": '\u0331', // Macron below
+	"c": '\u0327', // Cedilla, e.g., \c{c} for ç
+	"d": '\u0323', // Dot below
+	"H": '\u030b', // Double acute, e.g., \H{o} for ő
+	"k": '\u0328', // Ogonek, e.g., \k{a} for ą
+	"r": '\u030a', // Ring above, e.g., \r{u} for ů
+	"u": '\u0306', // Breve, e.g., \u{g} for ğ
+	"v": '\u030c', // Caron, e.g., \v{s} for š
+}
+
+// latexLetters maps the LaTeX commands of special letters to Unicode.
+var latexLetters = map[string]string{
+	"AA": "Å", "aa": "å",
+	"AE": "Æ", "ae": "æ",
+	"L": "Ł", "l": "ł",
+	"O": "Ø", "o": "ø",
+	"OE": "Œ", "oe": "œ",
+	"i": "ı", "j": "ȷ",
+	"ss": "ß",
+}
+
+// latexAccentCommands and latexLetterCommands are the reverse mappings of latexAccents and latexLetters.
+var latexAccentCommands, latexLetterCommands = reverseLaTeXMaps()
+
+// reverseLaTeXMaps returns the LaTeX commands of the combining characters and special letters.
+func reverseLaTeXMaps() (map[rune]string, map[string]string) {
+	accentCommands := make(map[rune]string, len(latexAccents))
+	for command, accent := range latexAccents {
+		accentCommands[accent] = command
+	}
+	letterCommands := make(map[string]string, len(latexLetters))
+	for command, letter := range latexLetters {
+		letterCommands[letter] = command
+	}
+	return accentCommands, letterCommands
+}
+
+// latexEscapes are the chars that are escaped with a backslash in LaTeX (e.g., \&).
+const latexEscapes = `&%$#_`
+
+// DecodeLaTeX converts the standard LaTeX accent commands (e.g., \"o, \'{e}, {\`a}, \c{c})
+// and special letters (e.g., \ss, \o, \ae) to Unicode, e.g., M\"uller becomes Müller.
+// The escaped chars \&, \%, \$, \#, and \_ are unescaped. Braces enclosing only a
+// converted command (e.g., {\"o}) are removed. Unknown commands are left verbatim.
+// DecodeLaTeX can be used as an optional post-processing step on field values,
+// e.g., before exporting entries to JSON or RIS.
+func DecodeLaTeX(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var builder strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '{' && i+1 < len(s) && s[i+1] == '\\':
+			// Remove the braces of a group like {\"o}
+			if decoded, end, ok := decodeLaTeXCommand(s, i+1); ok && end < len(s) && s[end] == '}' {
+				builder.WriteString(decoded)
+				i = end + 1
+				continue
+			}
+		case s[i] == '\\':
+			if decoded, end, ok := decodeLaTeXCommand(s, i); ok {
+				builder.WriteString(decoded)
+				i = end
+				continue
+			}
+		}
+		builder.WriteByte(s[i])
+		i++
+	}
+	return builder.String()
+}
+
+// EncodeLaTeX converts accented letters to LaTeX accent commands enclosed in braces
+// (e.g., ö to {\"o}, é to {\'e}, ç to {\c{c}}) and special letters to their commands
+// (e.g., ß to {\ss}), so the value can be processed by BibTeX engines without Unicode support.
+// Chars without a known LaTeX command are left as they are. EncodeLaTeX is the inverse
+// of DecodeLaTeX for the supported accents and letters.
+func EncodeLaTeX(s string) string {
+	var builder strings.Builder
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			builder.WriteRune(r)
+			continue
+		}
+		if command, ok := latexLetterCommands[string(r)]; ok {
+			builder.WriteString(`{\` + command + "}")
+			continue
+		}
+		// Split into the base letter and the accent, e.g., é into e and U+0301
+		decomposed := []rune(norm.NFD.String(string(r)))
+		if len(decomposed) == 2 && decomposed[0] < utf8.RuneSelf && isASCIILetter(byte(decomposed[0])) {
+			if command, ok := latexAccentCommands[decomposed[1]]; ok {
+				base := string(decomposed[0])
+				if isASCIILetter(command[0]) {
+					// Accent commands with a letter name need braces, e.g., {\c{c}}
+					base = "{" + base + "}"
+				}
+				builder.WriteString(`{\` + command + base + "}")
+				continue
+			}
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// decodeLaTeXCommand converts the LaTeX command starting with the '\' at the given index.
+// It returns the converted text and the index after the command, or false if the
+// command is not known.
+func decodeLaTeXCommand(s string, start int) (string, int, bool) {
+	name, end := latexCommandName(s, start)
+	if name == "" {
+		return "", start, false
+	}
+	// Escaped chars like \&
+	if len(name) == 1 && strings.Contains(latexEscapes, name) {
+		return name, end, true
+	}
+	// Special letters like \ss, terminated by {} or a white space
+	if letter, ok := latexLetters[name]; ok {
+		switch {
+		case strings.HasPrefix(s[end:], "{}"):
+			end += 2
+		case end < len(s) && s[end] == ' ':
+			end++
+		case end < len(s) && isASCIILetter(s[end]):
+			return "", start, false
+		}
+		return letter, end, true
+	}
+	// Accents like \"o, \"{o}, or \c c
+	accent, ok := latexAccents[name]
+	if !ok {
+		return "", start, false
+	}
+	base, end, ok := latexAccentArgument(s, end, isASCIILetter(name[0]))
+	if !ok {
+		return "", start, false
+	}
+	return norm.NFC.String(base + string(accent)), end, true
+}
+
+// latexCommandName returns the name of the LaTeX command starting with the '\' at the
+// given index and the index after the name. Names are either letters (e.g., ss) or a
+// single other char (e.g., ").
+func latexCommandName(s string, start int) (string, int) {
+	end := start + 1
+	if end >= len(s) {
+		return "", start
+	}
+	if !isASCIILetter(s[end]) {
+		return s[end : end+1], end + 1
+	}
+	for end < len(s) && isASCIILetter(s[end]) {
+		end++
+	}
+	return s[start+1 : end], end
+}
+
+// latexAccentArgument returns the base letter of an accent command starting at the given
+// index, which is either enclosed in braces (e.g., {o} or {\i}) or a single letter.
+// Accent commands with a letter name (e.g., \c) are separated from an unbraced letter by
+// white spaces, which are skipped. The dotless \i and \j are converted to i and j.
+func latexAccentArgument(s string, start int, skipSpaces bool) (string, int, bool) {
+	end := start
+	if skipSpaces {
+		for end < len(s) && s[end] == ' ' {
+			end++
+		}
+		if end == start && (end >= len(s) || s[end] != '{') {
+			return "", start, false
+		}
+	}
+	braced := end < len(s) && s[end] == '{'
+	if braced {
+		end++
+	}
+	if end >= len(s) {
+		return "", start, false
+	}
+	var base string
+	if s[end] == '\\' {
+		name, nameEnd := latexCommandName(s, end)
+		if name != "i" && name != "j" {
+			return "", start, false
+		}
+		base, end = name, nameEnd
+	} else {
+		r, size := utf8.DecodeRuneInString(s[end:])
+		if !unicode.IsLetter(r) {
+			return "", start, false
+		}
+		base, end = string(r), end+size
+	}
+	if braced {
+		if end >= len(s) || s[end] != '}' {
+			return "", start, false
+		}
+		end++
+	}
+	return base, end, true
+}
+
+// isASCIILetter checks if the char is an ASCII letter.
+func isASCIILetter(char byte) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
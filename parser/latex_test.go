@@ -0,0 +1,57 @@
+// Unit-tests for latex.go
+package parser
+
+import "testing"
+
+func TestDecodeLaTeX(t *testing.T) {
+	cases := map[string]string{
+		// Case 1: Accent commands with and without braces
+		`M\"uller`:                          "Müller",
+		"Gr\\`{a}cia":                       "Gràcia",
+		`{\'e}t{\'{e}}`:                     "été",
+		`\^o \~n \=a \.z`:                   "ô ñ ā ż",
+		`Fran\c{c}ois, \c cedilla`:          "François, çedilla",
+		`\v{S}koda \H{o} \k{a} \r{u} \u{g}`: "Škoda ő ą ů ğ",
+		`\'{\i}`:                            "í",
+		// Case 2: Special letters
+		`Stra\ss e, \ss{}, {\o}st, \AE ra`: "Straße, ß, øst, Æra",
+		// Case 3: Escaped chars
+		`Smith \& Sons, 100\%`: "Smith & Sons, 100%",
+		// Case 4: Unknown commands and text without commands are left verbatim
+		`\emph{Go} and \textbf{x}`: `\emph{Go} and \textbf{x}`,
+		`\"`:                       `\"`,
+		`\c`:                       `\c`,
+		`{Plain} text`:             `{Plain} text`,
+	}
+	for input, expected := range cases {
+		if result := DecodeLaTeX(input); expected != result {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, result)
+		}
+	}
+}
+
+func TestEncodeLaTeX(t *testing.T) {
+	cases := map[string]string{
+		// Case 1: Accented letters
+		"Müller":   `M{\"u}ller`,
+		"été":      `{\'e}t{\'e}`,
+		"François": `Fran{\c{c}}ois`,
+		"Škoda":    `{\v{S}}koda`,
+		// Case 2: Special letters
+		"Straße, Øst": `Stra{\ss}e, {\O}st`,
+		// Case 3: Chars without LaTeX command and ASCII are left as they are
+		"Plain ASCII & {braces}": "Plain ASCII & {braces}",
+		"日本":                     "日本",
+	}
+	for input, expected := range cases {
+		if result := EncodeLaTeX(input); expected != result {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, result)
+		}
+	}
+	// Case 4: Round trip
+	for _, input := range []string{"Müller", "García", "Ångström", "Dvořák", "Łódź", "Œuvre", "Ærø", "naïve", "Sørensen", "Çelik"} {
+		if result := DecodeLaTeX(EncodeLaTeX(input)); input != result {
+			t.Errorf("Expected '%#v', but got '%#v'", input, result)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// The links.go source file includes functions to collect the links of BibTeX entries
+//
+// Links: returns the URLs of the url, doi, and eprint fields of a BibTeXFile
+// CheckLinks: requests the URLs of links in parallel to find dead links
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Define errors
+type ErrDeadLink struct {
+	Key        string
+	URL        string
+	StatusCode int   // The HTTP status code of the response (0 if there was no response).
+	Err        error // The error of the request (nil if there was a response).
+}
+
+func (e *ErrDeadLink) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("Error checking BibTeX entry '%s': link '%s' is dead (%v)", e.Key, e.URL, e.Err)
+	}
+	return fmt.Sprintf("Error checking BibTeX entry '%s': link '%s' is dead (HTTP %d)", e.Key, e.URL, e.StatusCode)
+}
+
+func (e *ErrDeadLink) Unwrap() error {
+	return e.Err
+}
+
+// Link is a URL referenced by one or more entries, see Links.
+type Link struct {
+	URL   string   // The URL, e.g., https://doi.org/10.1000/182 for a DOI.
+	Field string   // The field of the first entry with the URL (url, doi, or eprint).
+	Value string   // The value of this field, e.g., the DOI 10.1000/182.
+	Keys  []string // The keys of all entries with the URL, in the order of the file.
+}
+
+// linkFields are the fields with links, in the order they are collected by Links.
+var linkFields = []string{"doi", "url", "eprint"}
+
+// Prefixes of DOIs written as URLs or with a scheme (e.g., https://doi.org/10.1000/182)
+var doiPrefixes = []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"}
+
+// The URLs of the eprint types supported by biblatex, followed by the eprint
+var eprintURLs = map[string]string{
+	"arxiv":       "https://arxiv.org/abs/",
+	"hdl":         "https://hdl.handle.net/",
+	"jstor":       "https://www.jstor.org/stable/",
+	"pubmed":      "https://pubmed.ncbi.nlm.nih.gov/",
+	"googlebooks": "https://books.google.com/books?id=",
+}
+
+// Links returns the URLs of the url, doi, and eprint fields of all entries, e.g., as the
+// input of a link checker. DOIs are turned into https://doi.org/ URLs and eprints into
+// the URL of their eprinttype (or archiveprefix), e.g., https://arxiv.org/abs/ for arXiv;
+// eprints without a known type are skipped. Braces and LaTeX escapes like \% are removed
+// from the URLs. Each URL is returned once with the keys of all entries referencing it,
+// in the order the URLs first appear in the file.
+func (f *BibTeXFile) Links() []Link {
+	var links []Link
+	indices := make(map[string]int)
+	for _, entry := range f.Entries {
+		for _, field := range linkFields {
+			value := strings.TrimSpace(entry.Fields[field])
+			link := entry.linkURL(field, value)
+			if link == "" {
+				continue
+			}
+			index, ok := indices[link]
+			if !ok {
+				index = len(links)
+				indices[link] = index
+				links = append(links, Link{URL: link, Field: field, Value: value})
+			}
+			// An entry may reference the same URL in several fields
+			if keys := links[index].Keys; len(keys) == 0 || keys[len(keys)-1] != entry.Key {
+				links[index].Keys = append(keys, entry.Key)
+			}
+		}
+	}
+	return links
+}
+
+// linkURL returns the URL of the value of the field (see Links), or "" if there is none.
+func (e *Entry) linkURL(field, value string) string {
+	if value == "" {
+		return ""
+	}
+	switch field {
+	case "doi":
+		for _, prefix := range doiPrefixes {
+			if len(value) > len(prefix) && strings.EqualFold(value[:len(prefix)], prefix) {
+				value = value[len(prefix):]
+				break
+			}
+		}
+		return "https://doi.org/" + value
+	case "eprint":
+		eprintType := e.Fields["eprinttype"]
+		if eprintType == "" {
+			eprintType = e.Fields["archiveprefix"]
+		}
+		prefix, ok := eprintURLs[strings.ToLower(strings.TrimSpace(eprintType))]
+		if !ok {
+			return ""
+		}
+		return prefix + value
+	default:
+		return urlUnescaper.Replace(strings.TrimSpace(stripBraces(value)))
+	}
+}
+
+// LinkResult is the result of checking a Link, see CheckLinks.
+type LinkResult struct {
+	Link       Link
+	StatusCode int   // The HTTP status code of the last response (0 if there was no response).
+	Err        error // The error of the request, e.g., a connection error or a timeout.
+}
+
+// Dead reports whether the request of the link failed or its response was no success
+// (2xx) or redirect (3xx).
+func (r LinkResult) Dead() bool {
+	return r.Err != nil || r.StatusCode < 200 || r.StatusCode >= 400
+}
+
+// Errors returns one ErrDeadLink per key of a dead link (see Dead), or nil if the link
+// is alive.
+func (r LinkResult) Errors() []error {
+	if !r.Dead() {
+		return nil
+	}
+	errs := make([]error, 0, len(r.Link.Keys))
+	for _, key := range r.Link.Keys {
+		errs = append(errs, &ErrDeadLink{Key: key, URL: r.Link.URL, StatusCode: r.StatusCode, Err: r.Err})
+	}
+	return errs
+}
+
+// The number of parallel requests of CheckLinks if no concurrency is given
+const defaultLinkConcurrency = 8
+
+// The timeout of a single request of CheckLinks
+var linkTimeout = 15 * time.Second
+
+// CheckLinks requests the URLs of the links (e.g., returned by Links) with the given number
+// of parallel requests (8 if concurrency < 1) and returns the results in the order of the
+// links. Each URL is requested with HEAD first and with GET if the server rejects the HEAD
+// request; redirects are followed. A request is canceled after 15 seconds, and all
+// remaining requests fail once ctx is canceled.
+// CheckLinks requires network access and is therefore never part of Validate.
+func CheckLinks(ctx context.Context, links []Link, concurrency int) []LinkResult {
+	if concurrency < 1 {
+		concurrency = defaultLinkConcurrency
+	}
+	results := make([]LinkResult, len(links))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(links)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every index is written by exactly one worker, so no locking is needed
+			for i := range indices {
+				results[i] = checkLink(ctx, links[i])
+			}
+		}()
+	}
+	for i := range links {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	return results
+}
+
+// checkLink requests the URL of the link, see CheckLinks.
+func checkLink(ctx context.Context, link Link) LinkResult {
+	ctx, cancel := context.WithTimeout(ctx, linkTimeout)
+	defer cancel()
+	result := LinkResult{Link: link}
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, link.URL, nil)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			result.StatusCode, result.Err = 0, err
+			return result
+		}
+		// The body is not needed, only the status
+		resp.Body.Close()
+		result.StatusCode = resp.StatusCode
+		// Some servers reject HEAD requests (e.g., with 403 or 405), but answer GET requests
+		if resp.StatusCode < 400 {
+			return result
+		}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+// Unit-tests for links.go
+package parser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLinks(t *testing.T) {
+	bibtexFile, _ := ParseString(`@article{a, doi = {10.1000/182}, url = {https://example.org/a\%20b}}
+@article{b, doi = {https://doi.org/10.1000/182}, url = {https://doi.org/10.1000/182}}
+@misc{c, eprint = {2301.01234}, eprinttype = {arXiv}}
+@misc{d, eprint = {2301.01234}, archiveprefix = {arXiv}}
+@misc{e, eprint = {12345}, eprinttype = {unknown}, url = {}}
+@misc{f, title = {No links}}`)
+
+	// Case 1: DOIs, URLs, and eprints, each URL once with all its keys
+	expected := []Link{
+		{URL: "https://doi.org/10.1000/182", Field: "doi", Value: "10.1000/182", Keys: []string{"a", "b"}},
+		{URL: "https://example.org/a%20b", Field: "url", Value: `https://example.org/a\%20b`, Keys: []string{"a"}},
+		{URL: "https://arxiv.org/abs/2301.01234", Field: "eprint", Value: "2301.01234", Keys: []string{"c", "d"}},
+	}
+	if links := bibtexFile.Links(); !reflect.DeepEqual(expected, links) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, links)
+	}
+
+	// Case 2: No links
+	if links := (&BibTeXFile{}).Links(); links != nil {
+		t.Errorf("Expected no links, but got '%#v'", links)
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/slow":
+			<-release
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	defaultTimeout := linkTimeout
+	linkTimeout = 100 * time.Millisecond
+	defer func() { linkTimeout = defaultTimeout }()
+	links := []Link{
+		{URL: server.URL + "/ok", Keys: []string{"a"}},
+		{URL: server.URL + "/redirect", Keys: []string{"b"}},
+		{URL: server.URL + "/get-only", Keys: []string{"c"}},
+		{URL: server.URL + "/missing", Keys: []string{"d", "e"}},
+		{URL: server.URL + "/slow", Keys: []string{"f"}},
+	}
+
+	// Case 1: Alive links, links answering GET requests only, missing and timed out links
+	results := CheckLinks(context.Background(), links, 2)
+	expectedStatus := []int{200, 200, 200, 404, 0}
+	expectedDead := []bool{false, false, false, true, true}
+	for i, result := range results {
+		if result.Link.URL != links[i].URL || result.StatusCode != expectedStatus[i] || result.Dead() != expectedDead[i] {
+			t.Errorf("Expected status %d (dead: %v) for '%s', but got '%#v'", expectedStatus[i], expectedDead[i], links[i].URL, result)
+		}
+	}
+	if !errors.Is(results[4].Err, context.DeadlineExceeded) {
+		t.Errorf("Expected '%#v', but got '%#v'", context.DeadlineExceeded, results[4].Err)
+	}
+
+	// Case 2: One error per key of a dead link
+	expected := []error{
+		&ErrDeadLink{Key: "d", URL: server.URL + "/missing", StatusCode: 404},
+		&ErrDeadLink{Key: "e", URL: server.URL + "/missing", StatusCode: 404},
+	}
+	if errs := results[3].Errors(); !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+	if errs := results[0].Errors(); errs != nil {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+
+	// Case 3: All links fail once the context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, result := range CheckLinks(ctx, links[:2], 0) {
+		if !errors.Is(result.Err, context.Canceled) || !result.Dead() {
+			t.Errorf("Expected '%#v', but got '%#v'", context.Canceled, result.Err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// The merge.go source file includes functions to combine several BibTeX files
+//
+// Merge: adds the entries of another BibTeXFile with a strategy for duplicate keys
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import "fmt"
+
+// Define errors
+type ErrKeyConflict struct {
+	Key string
+}
+
+func (e *ErrKeyConflict) Error() string {
+	return fmt.Sprintf("Error merging BibTeX files: key '%s' exists in both files", e.Key)
+}
+
+// ConflictStrategy decides how Merge handles entries with the same key in both files.
+type ConflictStrategy int
+
+const (
+	KeepFirst     ConflictStrategy = iota // Keep the entry of the receiver and skip the other one.
+	KeepLast                              // Replace the entry of the receiver with the other one.
+	ConflictError                         // Do not merge anything if there are conflicts.
+)
+
+// Merge adds the entries, @string macros, @comment blocks (and their disabled entries),
+// and @preamble of other to f.
+// Entries whose key already exists in f are conflicts, which are resolved by onConflict.
+// The merged entries keep a deterministic order: the entries of f, followed by the new
+// entries of other in their original order. With KeepLast, the replaced entries keep
+// their position in f. Macros are resolved like entries, but not reported.
+// Merge returns one ErrKeyConflict per conflicting key. With ConflictError, f is only
+// changed if there are no conflicts. Entries without a key never conflict. Repeated
+// keys within other are resolved by onConflict as well, but not reported.
+func (f *BibTeXFile) Merge(other *BibTeXFile, onConflict ConflictStrategy) []error {
+	var conflicts []error
+	positions := make(map[string]int, len(f.Entries))
+	for i, entry := range f.Entries {
+		if _, ok := positions[entry.Key]; !ok && entry.Key != "" {
+			positions[entry.Key] = i
+		}
+	}
+	for _, entry := range other.Entries {
+		if _, ok := positions[entry.Key]; ok {
+			conflicts = append(conflicts, &ErrKeyConflict{Key: entry.Key})
+		}
+	}
+	if onConflict == ConflictError && len(conflicts) > 0 {
+		return conflicts
+	}
+	// Merge entries
+	entries := append([]*Entry{}, f.Entries...)
+	for _, entry := range other.Entries {
+		position, ok := positions[entry.Key]
+		switch {
+		case !ok:
+			entries = append(entries, entry)
+			if entry.Key != "" {
+				positions[entry.Key] = len(entries) - 1
+			}
+		case onConflict == KeepLast:
+			entries[position] = entry
+		}
+	}
+	f.SetEntries(entries)
+	// Merge macros, comments, and preamble
+	if f.Macros == nil {
+		f.Macros = make(map[string]string, len(other.Macros))
+	}
+	for name, value := range other.Macros {
+		if _, ok := f.Macros[name]; !ok || onConflict == KeepLast {
+			f.Macros[name] = value
+		}
+	}
+	f.Comments = append(f.Comments, other.Comments...)
+	f.DisabledEntries = append(f.DisabledEntries, other.DisabledEntries...)
+	switch {
+	case f.Preamble == "":
+		f.Preamble = other.Preamble
+	case other.Preamble != "":
+		f.Preamble += " # " + other.Preamble
+	}
+	return conflicts
+}
@@ -0,0 +1,70 @@
+// Unit-tests for merge.go
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	first := `@string{pub = {ACM}}
+@misc{a, title = {A1}}
+@misc{b, title = {B1}}`
+	second := `@string{pub = {IEEE}}
+@comment{Second}
+@misc{c, title = {C2}}
+@misc{b, title = {B2}}
+@misc{d, title = {D2}}`
+	expectedConflicts := []error{&ErrKeyConflict{Key: "b"}}
+
+	// Case 1: Keep the entries of the first file
+	file1, _ := ParseNewBibTeXFile(strings.NewReader(first))
+	file2, _ := ParseNewBibTeXFile(strings.NewReader(second))
+	conflicts1 := file1.Merge(file2, KeepFirst)
+	if !reflect.DeepEqual(expectedConflicts, conflicts1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedConflicts, conflicts1)
+	}
+	expectedKeys := []string{"a", "b", "c", "d"}
+	if keys := entryKeys(file1.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+	if entry, _ := file1.LookupByKey("b"); entry.Fields["title"] != "B1" || file1.Macros["pub"] != "ACM" {
+		t.Errorf("Expected '%#v', but got '%#v'", "B1", entry.Fields["title"])
+	}
+	if !reflect.DeepEqual([]string{"Second"}, file1.Comments) {
+		t.Errorf("Expected '%#v', but got '%#v'", []string{"Second"}, file1.Comments)
+	}
+
+	// Case 2: Keep the entries of the second file at the position of the first
+	file1, _ = ParseNewBibTeXFile(strings.NewReader(first))
+	file2, _ = ParseNewBibTeXFile(strings.NewReader(second))
+	conflicts2 := file1.Merge(file2, KeepLast)
+	if !reflect.DeepEqual(expectedConflicts, conflicts2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedConflicts, conflicts2)
+	}
+	if keys := entryKeys(file1.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+	if entry, _ := file1.LookupByKey("b"); entry.Fields["title"] != "B2" || file1.Macros["pub"] != "IEEE" {
+		t.Errorf("Expected '%#v', but got '%#v'", "B2", entry.Fields["title"])
+	}
+
+	// Case 3: Nothing is merged on conflicts
+	file1, _ = ParseNewBibTeXFile(strings.NewReader(first))
+	file2, _ = ParseNewBibTeXFile(strings.NewReader(second))
+	conflicts3 := file1.Merge(file2, ConflictError)
+	if !reflect.DeepEqual(expectedConflicts, conflicts3) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedConflicts, conflicts3)
+	}
+	if keys := entryKeys(file1.Entries); !reflect.DeepEqual([]string{"a", "b"}, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", []string{"a", "b"}, keys)
+	}
+
+	// Case 4: No conflicts
+	file1, _ = ParseNewBibTeXFile(strings.NewReader(first))
+	file3, _ := ParseNewBibTeXFile(strings.NewReader(`@misc{e, title = {E}}`))
+	if conflicts4 := file1.Merge(file3, ConflictError); len(conflicts4) != 0 || len(file1.Entries) != 3 {
+		t.Errorf("Expected no conflicts, but got '%#v'", conflicts4)
+	}
+}
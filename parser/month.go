@@ -0,0 +1,130 @@
+// The month.go source file includes functions to recognize and normalize the month field
+//
+// ParseMonth: returns the number of a month given as number, macro, or name
+// NormalizeMonth: converts a month to a number or to the name in a locale
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Define errors
+type ErrUnknownMonth struct {
+	Key   string
+	Value string
+}
+
+func (e *ErrUnknownMonth) Error() string {
+	return fmt.Sprintf("Warning validating BibTeX entry '%s': unknown month '%s'", e.Key, e.Value)
+}
+
+// monthNames maps the supported locales to the names of the months (January first).
+var monthNames = map[string][12]string{
+	"en": {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	"de": {"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	"fr": {"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	"it": {"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+}
+
+// monthNumbers maps the lowercased month names of all locales, the BibTeX month macros
+// (e.g., mar), and common abbreviations to the month number.
+var monthNumbers = buildMonthNumbers()
+
+// buildMonthNumbers returns the month numbers of all names in monthNames and their abbreviations.
+func buildMonthNumbers() map[string]int {
+	numbers := map[string]int{"sept": 9, "mrz": 3, "mär": 3, "okt": 10, "dez": 12, "déc": 12}
+	for i, name := range monthNames["en"] {
+		// The month macros are the first three letters of the English names (e.g., jan)
+		numbers[strings.ToLower(name[:3])] = i + 1
+	}
+	for _, names := range monthNames {
+		for i, name := range names {
+			numbers[strings.ToLower(name)] = i + 1
+		}
+	}
+	return numbers
+}
+
+// ParseMonth returns the number (1 to 12) of the month, which can be given as a number
+// (e.g., 3 or 03), as a BibTeX month macro (e.g., mar), or as the name of the month in
+// one of the supported locales (en, de, fr, es, it), e.g., March, März, or mars.
+// Names are case-insensitive and Unicode-normalized; braces and a trailing '.' (e.g., {Mar.})
+// are ignored.
+// It returns false if the month is not recognized.
+func ParseMonth(value string) (int, bool) {
+	month := strings.TrimSuffix(strings.ToLower(norm.NFC.String(strings.TrimSpace(stripBraces(value)))), ".")
+	if number, err := strconv.Atoi(month); err == nil {
+		return number, number >= 1 && number <= 12
+	}
+	number, ok := monthNumbers[month]
+	return number, ok
+}
+
+// NormalizeMonth converts the month (see ParseMonth) to a canonical form: the number of
+// the month (e.g., 3, as expected by biblatex) if locale is empty, or the name of the
+// month in the given locale (e.g., March for en or März for de). It returns false if
+// the month or the locale is not known.
+func NormalizeMonth(value, locale string) (string, bool) {
+	number, ok := ParseMonth(value)
+	if !ok {
+		return "", false
+	}
+	if locale == "" {
+		return strconv.Itoa(number), true
+	}
+	names, ok := monthNames[strings.ToLower(locale)]
+	if !ok {
+		return "", false
+	}
+	return names[number-1], true
+}
+
+// NormalizeMonth sets the month field of the entry to its canonical form, see the
+// function NormalizeMonth. Entries without a month field are not changed. If the month
+// is not recognized, the field is kept and an ErrUnknownMonth is returned.
+func (e *Entry) NormalizeMonth(locale string) error {
+	value, ok := e.Fields["month"]
+	if !ok {
+		return nil
+	}
+	normalized, ok := NormalizeMonth(value, locale)
+	if !ok {
+		return &ErrUnknownMonth{Key: e.Key, Value: value}
+	}
+	e.Fields["month"] = normalized
+	return nil
+}
+
+// NormalizeMonths normalizes the month fields of all entries (see Entry.NormalizeMonth)
+// and returns an ErrUnknownMonth for every month that is not recognized.
+func (f *BibTeXFile) NormalizeMonths(locale string) []error {
+	var errs []error
+	for _, entry := range f.Entries {
+		if err := entry.NormalizeMonth(locale); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// monthNumber returns the number of the month of the entry (e.g., 3 for mar, March, or 3).
+func (e *Entry) monthNumber() (int, bool) {
+	return ParseMonth(e.Fields["month"])
+}
+
+// validateMonth checks if the month is recognized by ParseMonth and reports other
+// values as an ErrUnknownMonth warning.
+func validateMonth(key, value string) error {
+	if _, ok := ParseMonth(value); !ok {
+		return &ErrUnknownMonth{Key: key, Value: value}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Unit-tests for month.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMonth(t *testing.T) {
+	// Case 1: Numbers, macros, and names of all locales
+	cases := map[string]int{
+		"1": 1, "03": 3, "jan": 1, "January": 1, "{Mar.}": 3, "März": 3, "März": 3,
+		"mars": 3, "Sept.": 9, "diciembre": 12, "maggio": 5, " OKT ": 10,
+	}
+	for value, expected := range cases {
+		if number, ok := ParseMonth(value); !ok || expected != number {
+			t.Errorf("Expected '%#v' for '%s', but got '%#v'", expected, value, number)
+		}
+	}
+	// Case 2: Unknown months
+	for _, value := range []string{"", "0", "13", "spring", "March 3"} {
+		if number, ok := ParseMonth(value); ok {
+			t.Errorf("Expected no month for '%s', but got '%#v'", value, number)
+		}
+	}
+}
+
+func TestNormalizeMonths(t *testing.T) {
+	file, _ := ParseString(`@misc{a, month = {März}}
+@misc{b, month = jan}
+@misc{c, month = {Spring}}
+@misc{d, title = {No month}}`)
+
+	// Case 1: Numeric months, unknown months are kept and reported
+	expectedErrs := []error{&ErrUnknownMonth{Key: "c", Value: "Spring"}}
+	if errs := file.NormalizeMonths(""); !reflect.DeepEqual(expectedErrs, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedErrs, errs)
+	}
+	var months []string
+	for _, entry := range file.Entries {
+		months = append(months, entry.Fields["month"])
+	}
+	if expected := []string{"3", "1", "Spring", ""}; !reflect.DeepEqual(expected, months) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, months)
+	}
+
+	// Case 2: Month names of a locale
+	if month, ok := NormalizeMonth("3", "de"); !ok || month != "März" {
+		t.Errorf("Expected '%#v', but got '%#v'", "März", month)
+	}
+	if month, ok := NormalizeMonth("mar", "xx"); ok {
+		t.Errorf("Expected no month for an unknown locale, but got '%#v'", month)
+	}
+
+	// Case 3: Unknown months are warnings of Validate
+	if err := validateMonth("c", "Spring"); !IsWarning(err) {
+		t.Errorf("Expected a warning, but got '%#v'", err)
+	}
+}
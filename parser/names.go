@@ -0,0 +1,286 @@
+// The names.go source file includes functions to process BibTeX name lists like the author field
+//
+// Authors: splits the author field of an Entry into single names
+// ParseName: splits a single name into its four BibTeX name parts
+// NormalizeNameList: joins the names of a name list with exactly one " and "
+// ClassifyName: tells apart names written as "First Last" and as "Last, First"
+// CheckNameForms: reports entries whose authors are not written like most others
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Define errors
+type ErrInconsistentNameForm struct {
+	Key      string
+	Name     string   // The first author of the entry written in the uncommon form.
+	Form     NameForm // The form of the name.
+	Expected NameForm // The form of most authors in the file.
+}
+
+func (e *ErrInconsistentNameForm) Error() string {
+	return fmt.Sprintf("Warning checking BibTeX entry '%s': author '%s' is written as '%s', but most authors are written as '%s'", e.Key, e.Name, e.Form, e.Expected)
+}
+
+// NameForm is the form in which a name is written in a name list, see ClassifyName.
+type NameForm int
+
+const (
+	NameFormUnknown   NameForm = iota // A single word (e.g., Plato or {World Health Organization}) or "others".
+	NameFormFirstLast                 // First von Last, e.g., Ludwig van Beethoven.
+	NameFormLastFirst                 // von Last, First or von Last, Jr, First, e.g., van Beethoven, Ludwig.
+)
+
+// String returns the pattern of the name form, e.g., "Last, First".
+func (f NameForm) String() string {
+	switch f {
+	case NameFormFirstLast:
+		return "First Last"
+	case NameFormLastFirst:
+		return "Last, First"
+	default:
+		return "unknown"
+	}
+}
+
+// Authors returns the names of the author field, see SplitNames.
+func (e *Entry) Authors() []string {
+	return SplitNames(e.Fields["author"])
+}
+
+// SplitNames splits a BibTeX name list (e.g., an author or editor field) on all
+// ' and ' that are not enclosed in braces, so {Black and Decker} stays one name.
+// As in BibTeX, the separator is case-insensitive. All names are trimmed and empty names are dropped.
+func SplitNames(nameList string) []string {
+	var names []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(nameList); i++ {
+		switch nameList[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		default:
+			if depth == 0 && isNameSeparator(nameList, i) {
+				names = appendName(names, nameList[start:i])
+				// Skip "and" but keep the following white space for trimming
+				start = i + 4
+				i += 3
+			}
+		}
+	}
+	return appendName(names, nameList[start:])
+}
+
+// nameListFields are the BibTeX and biblatex fields containing name lists.
+var nameListFields = map[string]bool{
+	"afterword": true, "annotator": true, "author": true, "bookauthor": true, "commentator": true,
+	"editor": true, "editora": true, "editorb": true, "editorc": true, "foreword": true,
+	"holder": true, "introduction": true, "shortauthor": true, "shorteditor": true, "translator": true,
+}
+
+// NormalizeNameList joins the names of the name list (see SplitNames) with exactly one
+// " and ", e.g., "Doe, Jane\tAND  Roe, Richard" becomes "Doe, Jane and Roe, Richard".
+// An "and" enclosed in braces (e.g., {Black and Decker}) is part of a name and kept as it is.
+// Empty names (e.g., of "Doe and and Roe") are dropped.
+func NormalizeNameList(nameList string) string {
+	return strings.Join(SplitNames(nameList), " and ")
+}
+
+// isNameSeparator checks if the string contains a white space followed by "and"
+// and another white space at the given index.
+func isNameSeparator(s string, index int) bool {
+	return index+5 <= len(s) && unicode.IsSpace(rune(s[index])) && strings.EqualFold(s[index+1:index+4], "and") && unicode.IsSpace(rune(s[index+4]))
+}
+
+// appendName appends the trimmed name if it is not empty.
+func appendName(names []string, name string) []string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return names
+	}
+	return append(names, name)
+}
+
+// ParseName splits a single BibTeX name into its four parts first, von, last, and jr.
+// It supports the three BibTeX name forms:
+//
+//	First von Last
+//	von Last, First
+//	von Last, Jr, First
+//
+// Words starting with a lowercase letter belong to the von part (e.g., "van" in
+// "Ludwig van Beethoven"). Brace groups like {O'Connor} are never split and do not
+// count as lowercase words.
+func ParseName(name string) (first, von, last, jr string) {
+	parts := splitNameParts(name)
+	switch len(parts) {
+	case 0:
+		return "", "", "", ""
+	case 1:
+		// First von Last
+		words := splitNameWords(parts[0])
+		if len(words) == 0 {
+			return "", "", "", ""
+		}
+		// The last word always belongs to the last name
+		vonStart, vonEnd := -1, -1
+		for i, word := range words[:len(words)-1] {
+			if isLowerCaseWord(word) {
+				if vonStart == -1 {
+					vonStart = i
+				}
+				vonEnd = i + 1
+			}
+		}
+		if vonStart == -1 {
+			return strings.Join(words[:len(words)-1], " "), "", words[len(words)-1], ""
+		}
+		return strings.Join(words[:vonStart], " "), strings.Join(words[vonStart:vonEnd], " "), strings.Join(words[vonEnd:], " "), ""
+	default:
+		// von Last, First or von Last, Jr, First
+		first = parts[len(parts)-1]
+		if len(parts) > 2 {
+			jr = strings.Join(parts[1:len(parts)-1], ", ")
+		}
+		von, last = splitVonLast(splitNameWords(parts[0]))
+		return first, von, last, jr
+	}
+}
+
+// ClassifyName returns the form of a single BibTeX name (see ParseName): NameFormLastFirst
+// if the name contains a ',' outside of braces, NameFormFirstLast if it consists of several
+// words, and NameFormUnknown otherwise, as single words like Plato or a brace group like
+// {World Health Organization} can be written in both forms.
+func ClassifyName(name string) NameForm {
+	parts := splitNameParts(name)
+	switch {
+	case len(parts) > 1:
+		return NameFormLastFirst
+	case len(parts) == 1 && len(splitNameWords(parts[0])) > 1:
+		return NameFormFirstLast
+	default:
+		return NameFormUnknown
+	}
+}
+
+// CheckNameForms checks that the authors of all entries are written in the same form (see
+// ClassifyName), either all as "First Last" or all as "Last, First". The form used by most
+// authors of the file (or "Last, First" if both are used equally often) is expected, and
+// every entry with an author in the other form is reported once as an ErrInconsistentNameForm
+// warning. Names of the unknown form are ignored.
+func (f *BibTeXFile) CheckNameForms() []error {
+	counts := make(map[NameForm]int)
+	for _, entry := range f.Entries {
+		for _, name := range entry.Authors() {
+			counts[ClassifyName(name)]++
+		}
+	}
+	if counts[NameFormFirstLast] == 0 || counts[NameFormLastFirst] == 0 {
+		return nil
+	}
+	expected := NameFormLastFirst
+	if counts[NameFormFirstLast] > counts[NameFormLastFirst] {
+		expected = NameFormFirstLast
+	}
+	var errs []error
+	for _, entry := range f.Entries {
+		for _, name := range entry.Authors() {
+			if form := ClassifyName(name); form != expected && form != NameFormUnknown {
+				errs = append(errs, &ErrInconsistentNameForm{Key: entry.Key, Name: name, Form: form, Expected: expected})
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// splitVonLast splits the words of a "von Last" part. The von part is the longest
+// sequence of words ending with a lowercase word, but the last word always belongs to the last name.
+func splitVonLast(words []string) (von, last string) {
+	if len(words) == 0 {
+		return "", ""
+	}
+	vonEnd := 0
+	for i, word := range words[:len(words)-1] {
+		if isLowerCaseWord(word) {
+			vonEnd = i + 1
+		}
+	}
+	return strings.Join(words[:vonEnd], " "), strings.Join(words[vonEnd:], " ")
+}
+
+// splitNameParts splits a name on all ',' that are not enclosed in braces and trims the parts.
+func splitNameParts(name string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(name[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	last := strings.TrimSpace(name[start:])
+	if last == "" && len(parts) == 0 {
+		return nil
+	}
+	return append(parts, last)
+}
+
+// splitNameWords splits a name part on all white spaces (and '~') that are not enclosed in braces.
+func splitNameWords(part string) []string {
+	var words []string
+	var builder strings.Builder
+	depth := 0
+	for _, char := range part {
+		switch {
+		case char == '{':
+			depth++
+		case char == '}':
+			depth--
+		case depth == 0 && (unicode.IsSpace(char) || char == '~'):
+			if builder.Len() > 0 {
+				words = append(words, builder.String())
+				builder.Reset()
+			}
+			continue
+		}
+		builder.WriteRune(char)
+	}
+	if builder.Len() > 0 {
+		words = append(words, builder.String())
+	}
+	return words
+}
+
+// isLowerCaseWord checks if the first letter of a word outside of braces is lowercase.
+// Words without letters outside of braces (e.g., {O'Connor}) are not lowercase.
+func isLowerCaseWord(word string) bool {
+	depth := 0
+	for _, char := range word {
+		switch {
+		case char == '{':
+			depth++
+		case char == '}':
+			depth--
+		case depth == 0 && unicode.IsLetter(char):
+			return unicode.IsLower(char)
+		}
+	}
+	return false
+}
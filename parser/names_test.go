@@ -0,0 +1,128 @@
+// Unit-tests for names.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuthors(t *testing.T) {
+	// Case 1: Names from the existing fixtures
+	entry1, _ := ParseNewEntry(`@book{schmidt2024,author = {Schmidt, Anna and Müller, Bernd and {O'Connor}, Claire and García, Diego}}`)
+	expected1 := []string{"Schmidt, Anna", "Müller, Bernd", "{O'Connor}, Claire", "García, Diego"}
+	if !reflect.DeepEqual(expected1, entry1.Authors()) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, entry1.Authors())
+	}
+
+	// Case 2: ' and ' inside braces, case-insensitive separator
+	expected2 := []string{"{Black and Decker}", "John Smith", "Alice Johnson", "others"}
+	result2 := SplitNames("{Black and Decker} AND John Smith and  Alice Johnson and others")
+	if !reflect.DeepEqual(expected2, result2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, result2)
+	}
+
+	// Case 3: Names containing "and" are not split
+	expected3 := []string{"Alexander Anderson", "Sandra Band"}
+	result3 := SplitNames("Alexander Anderson and Sandra Band")
+	if !reflect.DeepEqual(expected3, result3) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, result3)
+	}
+
+	// Case 4: No author field
+	entry4, _ := ParseNewEntry(`@misc{doe2021, title = {No Author}}`)
+	if len(entry4.Authors()) != 0 {
+		t.Errorf("Expected no authors, but got '%#v'", entry4.Authors())
+	}
+}
+
+func TestNormalizeNameList(t *testing.T) {
+	// Case 1: Irregular white spaces and case around the separators
+	cases := map[string]string{
+		"Doe, Jane   and\tRoe, Richard":     "Doe, Jane and Roe, Richard",
+		" Doe, Jane AND Roe, Richard ":      "Doe, Jane and Roe, Richard",
+		"Doe, Jane and and Roe, Richard":    "Doe, Jane and Roe, Richard",
+		"Jane Doe":                          "Jane Doe",
+		"Anderson, Jane and Sandy Rand":     "Anderson, Jane and Sandy Rand",
+		"{Black  and   Decker} and  Doe, J": "{Black  and   Decker} and Doe, J",
+	}
+	for nameList, expected := range cases {
+		if normalized := NormalizeNameList(nameList); expected != normalized {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, normalized)
+		}
+	}
+
+	// Case 2: Name lists are normalized when formatting, braced names are kept
+	entry, _ := ParseNewEntry("@book{b, author = {Doe, Jane  and\n  {Black and Decker}}, editor = {Roe,  R. AND Poe, E.}, title = {Smith  and  Sons}}")
+	expected := "@book{b,\n  author = {Doe, Jane and {Black and Decker}},\n  editor = {Roe, R. and Poe, E.},\n  title = {Smith and Sons}\n}"
+	if formatted := entry.String(); expected != formatted {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, formatted)
+	}
+}
+
+func TestParseName(t *testing.T) {
+	cases := map[string][4]string{
+		"García, Diego":                             {"Diego", "", "García", ""},
+		"{O'Connor}, Claire":                        {"Claire", "", "{O'Connor}", ""},
+		"Donald E. Knuth":                           {"Donald E.", "", "Knuth", ""},
+		"Ludwig van Beethoven":                      {"Ludwig", "van", "Beethoven", ""},
+		"van Beethoven, Ludwig":                     {"Ludwig", "van", "Beethoven", ""},
+		"Charles Louis Xavier de la Vallée Poussin": {"Charles Louis Xavier", "de la", "Vallée Poussin", ""},
+		"Ford, Jr., Henry":                          {"Henry", "", "Ford", "Jr."},
+		"{Barnes and Noble, Inc.}":                  {"", "", "{Barnes and Noble, Inc.}", ""},
+		"Knuth":                                     {"", "", "Knuth", ""},
+		"":                                          {"", "", "", ""},
+	}
+	for name, expected := range cases {
+		first, von, last, jr := ParseName(name)
+		result := [4]string{first, von, last, jr}
+		if expected != result {
+			t.Errorf("Expected '%#v' for '%s', but got '%#v'", expected, name, result)
+		}
+	}
+}
+
+func TestClassifyName(t *testing.T) {
+	cases := map[string]NameForm{
+		"Donald E. Knuth":             NameFormFirstLast,
+		"Ludwig van Beethoven":        NameFormFirstLast,
+		"van Beethoven, Ludwig":       NameFormLastFirst,
+		"Ford, Jr., Henry":            NameFormLastFirst,
+		"{Barnes and Noble, Inc.}":    NameFormUnknown,
+		"{World Health Organization}": NameFormUnknown,
+		"Plato":                       NameFormUnknown,
+		"others":                      NameFormUnknown,
+		"":                            NameFormUnknown,
+	}
+	for name, expected := range cases {
+		if form := ClassifyName(name); expected != form {
+			t.Errorf("Expected '%s' for '%s', but got '%s'", expected, name, form)
+		}
+	}
+}
+
+func TestCheckNameForms(t *testing.T) {
+	// Case 1: Entries with authors in the uncommon form are reported once
+	file, _ := ParseString(`@misc{a, author = {Doe, Jane and Roe, Richard}}
+@misc{b, author = {Knuth, Donald E. and Plato}}
+@misc{c, author = {Plato and Ada Lovelace and Alan Turing}}
+@misc{d, author = {{World Health Organization}}, editor = {Jane Doe}}`)
+	expected1 := []error{&ErrInconsistentNameForm{Key: "c", Name: "Ada Lovelace", Form: NameFormFirstLast, Expected: NameFormLastFirst}}
+	errs1 := file.CheckNameForms()
+	if !reflect.DeepEqual(expected1, errs1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, errs1)
+	}
+	if len(errs1) > 0 && !IsWarning(errs1[0]) {
+		t.Errorf("Expected '%#v' to be a warning", errs1[0])
+	}
+	expectedMessage := "Warning checking BibTeX entry 'c': author 'Ada Lovelace' is written as 'First Last', but most authors are written as 'Last, First'"
+	if len(errs1) > 0 && errs1[0].Error() != expectedMessage {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedMessage, errs1[0].Error())
+	}
+
+	// Case 2: Consistent files have no warnings
+	file2, _ := ParseString(`@misc{a, author = {Jane Doe and Richard Roe}}
+@misc{b, author = {Plato}}`)
+	if errs2 := file2.CheckNameForms(); len(errs2) != 0 {
+		t.Errorf("Expected no warnings, but got '%#v'", errs2)
+	}
+}
@@ -0,0 +1,29 @@
+// The pages.go source file includes functions to access the pages field of BibTeX entries
+//
+// Pages: returns the first and the last page of the pages field of an Entry
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import "strings"
+
+// Pages returns the first and the last page of the pages field, which is split on hyphens
+// (e.g., 123--145 or 123-145). For a single page (e.g., 42 or e12345), start and end are
+// equal. If the field lists several ranges (e.g., 1--5, 7--9), start is the first page
+// of the first range and end is the last page of the last range. Braces are removed.
+// It returns false if the entry has no page information.
+func (e *Entry) Pages() (start, end string, ok bool) {
+	var pages []string
+	for _, pageRange := range strings.Split(stripBraces(e.Fields["pages"]), ",") {
+		for _, page := range regexPageRangeSeparator.Split(strings.TrimSpace(pageRange), -1) {
+			if page != "" {
+				pages = append(pages, page)
+			}
+		}
+	}
+	if len(pages) == 0 {
+		return "", "", false
+	}
+	return pages[0], pages[len(pages)-1], true
+}
@@ -0,0 +1,36 @@
+// Unit-tests for pages.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPages(t *testing.T) {
+	// Case 1: Page ranges and single pages
+	cases := map[string][]string{
+		"123--145":    {"123", "145"},
+		"123-145":     {"123", "145"},
+		" 12 --- 14 ": {"12", "14"},
+		"{e12345}":    {"e12345", "e12345"},
+		"42":          {"42", "42"},
+		"1--5, 7--9":  {"1", "9"},
+		"xii--xiv, 3": {"xii", "3"},
+		"S12--S14":    {"S12", "S14"},
+		"100--":       {"100", "100"},
+	}
+	for value, expected := range cases {
+		entry := &Entry{Fields: map[string]string{"pages": value}}
+		start, end, ok := entry.Pages()
+		if !ok || !reflect.DeepEqual(expected, []string{start, end}) {
+			t.Errorf("Expected '%#v' for '%s', but got '%#v'", expected, value, []string{start, end})
+		}
+	}
+
+	// Case 2: No page information
+	for _, entry := range []*Entry{{Fields: map[string]string{}}, {Fields: map[string]string{"pages": " {} "}}} {
+		if start, end, ok := entry.Pages(); ok {
+			t.Errorf("Expected no pages, but got '%#v'", []string{start, end})
+		}
+	}
+}
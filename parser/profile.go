@@ -0,0 +1,373 @@
+// The profile.go source file includes the validation profiles for the BibTeX dialects
+//
+// ValidationProfile: defines the known entry types, their field rules, and the known and discouraged fields
+// DefaultProfile: the standard BibTeX entry types with the BibTeX and biblatex fields
+// BibTeXStandard: the standard BibTeX entry types and fields
+// Biblatex: the biblatex entry types and fields
+// LookupProfile: returns a built-in profile by its name
+// LoadValidationProfile: reads a custom profile from a JSON or YAML file
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Define errors
+type ErrInvalidProfile struct {
+	Message string
+}
+
+func (e *ErrInvalidProfile) Error() string {
+	return fmt.Sprintf("Error loading validation profile: %s", e.Message)
+}
+
+// ValidationProfile defines which entry types and fields ValidateWithOptions considers
+// valid, see ValidateOptions.Profile. Entry type and field names are lowercase.
+type ValidationProfile struct {
+	Name              string
+	EntryTypes        map[EntryType][]FieldRule // The known entry types and the rules for their fields.
+	KnownFields       map[string]bool           // The known fields, others are ErrUnknownField warnings (no check if nil).
+	DiscouragedFields map[EntryType][]string    // The fields of other entry types, e.g., journal on a book (ErrDiscouragedField warnings).
+}
+
+// DefaultProfile is used if no profile is given. It requires the fields of the standard
+// BibTeX entry types and of biblatex entry sets (@set), accepts biblatex @xdata entries
+// without required fields, and knows the biblatex fields and common fields of reference
+// managers (e.g., doi or url), as mixed files are common.
+var DefaultProfile = &ValidationProfile{
+	Name:              "default",
+	EntryTypes:        withEntryTypes(fieldRules, map[EntryType][]FieldRule{EntrySet: {requires("entryset")}, EntryXData: {}}),
+	KnownFields:       knownFields,
+	DiscouragedFields: biblatexDiscouragedFields(),
+}
+
+// BibTeXStandard is the profile of the standard BibTeX styles (e.g., plain): the standard
+// entry types, the BibTeX fields and common fields of reference managers, and the biblatex
+// sorting fields (e.g., sortkey), which BibTeX ignores.
+var BibTeXStandard = &ValidationProfile{
+	Name:              "bibtex",
+	EntryTypes:        fieldRules,
+	KnownFields:       mergeFieldSets(bibtexFields, sortingFields, commonFields),
+	DiscouragedFields: discouragedFields,
+}
+
+// Biblatex is the profile of biblatex: its entry types (including the BibTeX types it
+// supports as aliases, e.g., phdthesis) with the required fields of the biblatex manual,
+// and all BibTeX, biblatex, and common fields. As biblatex maps the BibTeX field names,
+// they satisfy the required fields, e.g., journal for journaltitle or year for date.
+var Biblatex = &ValidationProfile{
+	Name:              "biblatex",
+	EntryTypes:        biblatexRules(),
+	KnownFields:       knownFields,
+	DiscouragedFields: biblatexDiscouragedFields(),
+}
+
+// builtinProfiles are the profiles returned by LookupProfile.
+var builtinProfiles = []*ValidationProfile{DefaultProfile, BibTeXStandard, Biblatex}
+
+// LookupProfile returns the built-in profile with the given name (default, bibtex, or
+// biblatex), ignoring the case. It returns false if there is no such profile.
+func LookupProfile(name string) (*ValidationProfile, bool) {
+	for _, profile := range builtinProfiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, true
+		}
+	}
+	return nil, false
+}
+
+// jsonProfile is the JSON (and YAML) representation of a ValidationProfile read by
+// LoadValidationProfile, see profile.schema.json.
+type jsonProfile struct {
+	Name              string                     `json:"name" yaml:"name"`
+	Extends           string                     `json:"extends" yaml:"extends"`
+	EntryTypes        map[string][]jsonFieldRule `json:"entryTypes" yaml:"entryTypes"`
+	KnownFields       []string                   `json:"knownFields" yaml:"knownFields"`
+	DiscouragedFields map[string][]string        `json:"discouragedFields" yaml:"discouragedFields"`
+}
+
+// jsonFieldRule is the JSON (and YAML) representation of a FieldRule.
+type jsonFieldRule struct {
+	Fields    []string `json:"fields" yaml:"fields"`
+	Optional  bool     `json:"optional" yaml:"optional"`
+	Exclusive bool     `json:"exclusive" yaml:"exclusive"`
+	Unless    string   `json:"unless" yaml:"unless"`
+}
+
+// Regex to match the entry type names of a profile file, like the entry types of entries
+var regexProfileEntryType = regexp.MustCompile(`^[a-zA-Z0-9_:-]+$`)
+
+// Regex to match the field names of a profile file, like the field names of entries
+var regexProfileField = regexFieldName
+
+// LoadValidationProfile reads a custom ValidationProfile from a JSON or YAML file following
+// profile.schema.json, e.g., to check the citation rules of an institution without
+// recompiling:
+//
+//	{
+//	  "name": "institute",
+//	  "extends": "default",
+//	  "entryTypes": {
+//	    "article": [{"fields": ["author"]}, {"fields": ["title"]}, {"fields": ["doi", "url"]}],
+//	    "dataset": [{"fields": ["author", "editor"]}, {"fields": ["title"]}]
+//	  },
+//	  "knownFields": ["projectid"],
+//	  "discouragedFields": {"dataset": ["journal", "publisher"]}
+//	}
+//
+// Input starting with '{' (after white space) is read as JSON, all other input as YAML
+// with the same properties:
+//
+//	name: institute
+//	extends: default
+//	entryTypes:
+//	  article:
+//	    - fields: [author]
+//	    - fields: [doi, url]
+//	knownFields: [projectid]
+//
+// A profile extending a built-in profile (see LookupProfile) starts with its entry types,
+// known fields, and discouraged fields; the entry types and discouraged fields of the file
+// replace those of the same entry types, and the known fields are added. Without extends, only the entry types of the file are
+// known, and all fields are accepted unless knownFields is given. The fields of the rules
+// and the discouraged fields are always known. Entry type and field names are lowercased,
+// and the name defaults to "custom".
+// The file itself is checked on load: LoadValidationProfile returns an ErrInvalidProfile
+// for invalid JSON or YAML, unknown properties, unknown base profiles, invalid names, rules
+// without fields (or exclusive rules with a single field), and discouraged fields of
+// unknown entry types.
+func LoadValidationProfile(r io.Reader) (*ValidationProfile, error) {
+	reader := bufio.NewReader(r)
+	var file jsonProfile
+	var err error
+	if isJSONObject(reader) {
+		file, err = decodeJSONProfile(reader)
+	} else {
+		file, err = decodeYAMLProfile(reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file.validationProfile()
+}
+
+// isJSONObject reports whether the next char of the reader after white space is '{'.
+func isJSONObject(reader *bufio.Reader) bool {
+	for i := 1; ; i++ {
+		prefix, _ := reader.Peek(i)
+		if len(prefix) < i {
+			return false
+		}
+		switch prefix[i-1] {
+		case ' ', '\t', '\n', '\r':
+		case '{':
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// decodeJSONProfile decodes a single JSON profile without unknown properties.
+func decodeJSONProfile(r io.Reader) (jsonProfile, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var file jsonProfile
+	if err := decoder.Decode(&file); err != nil {
+		return file, &ErrInvalidProfile{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return file, &ErrInvalidProfile{Message: "unexpected data after the profile"}
+	}
+	return file, nil
+}
+
+// decodeYAMLProfile decodes a single YAML profile (one document) without unknown properties.
+func decodeYAMLProfile(r io.Reader) (jsonProfile, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	var file jsonProfile
+	if err := decoder.Decode(&file); err != nil {
+		return file, &ErrInvalidProfile{Message: fmt.Sprintf("invalid YAML: %v", err)}
+	}
+	var next yaml.Node
+	if err := decoder.Decode(&next); !errors.Is(err, io.EOF) {
+		return file, &ErrInvalidProfile{Message: "unexpected data after the profile"}
+	}
+	return file, nil
+}
+
+// validationProfile checks the decoded profile file and returns its ValidationProfile,
+// see LoadValidationProfile.
+func (file jsonProfile) validationProfile() (*ValidationProfile, error) {
+	profile := &ValidationProfile{Name: file.Name, EntryTypes: make(map[EntryType][]FieldRule, len(file.EntryTypes))}
+	if profile.Name == "" {
+		profile.Name = "custom"
+	}
+	if file.Extends != "" {
+		base, ok := LookupProfile(file.Extends)
+		if !ok {
+			return nil, &ErrInvalidProfile{Message: fmt.Sprintf("unknown base profile '%s'", file.Extends)}
+		}
+		maps.Copy(profile.EntryTypes, base.EntryTypes)
+		profile.KnownFields = maps.Clone(base.KnownFields)
+		profile.DiscouragedFields = maps.Clone(base.DiscouragedFields)
+	} else if len(file.EntryTypes) == 0 {
+		return nil, &ErrInvalidProfile{Message: "no entry types (and no base profile to extend)"}
+	}
+	if file.KnownFields != nil && profile.KnownFields == nil {
+		profile.KnownFields = make(map[string]bool, len(file.KnownFields))
+	}
+	for _, field := range file.KnownFields {
+		if !regexProfileField.MatchString(field) {
+			return nil, &ErrInvalidProfile{Message: fmt.Sprintf("invalid known field '%s'", field)}
+		}
+		profile.KnownFields[strings.ToLower(field)] = true
+	}
+	fileEntryTypes := make(map[string]bool, len(file.EntryTypes))
+	for entryType, jsonRules := range file.EntryTypes {
+		if !regexProfileEntryType.MatchString(entryType) {
+			return nil, &ErrInvalidProfile{Message: fmt.Sprintf("invalid entry type '%s'", entryType)}
+		}
+		entryType = strings.ToLower(entryType)
+		if fileEntryTypes[entryType] {
+			return nil, &ErrInvalidProfile{Message: fmt.Sprintf("entry type '%s' is defined twice", entryType)}
+		}
+		fileEntryTypes[entryType] = true
+		rules := make([]FieldRule, 0, len(jsonRules))
+		for i, jsonRule := range jsonRules {
+			rule, err := jsonRule.fieldRule()
+			if err != nil {
+				return nil, &ErrInvalidProfile{Message: fmt.Sprintf("rule %d of entry type '%s': %s", i+1, entryType, err)}
+			}
+			if profile.KnownFields != nil {
+				for _, field := range rule.Fields {
+					profile.KnownFields[field] = true
+				}
+			}
+			rules = append(rules, rule)
+		}
+		profile.EntryTypes[EntryType(entryType)] = rules
+	}
+	if len(file.DiscouragedFields) > 0 && profile.DiscouragedFields == nil {
+		profile.DiscouragedFields = make(map[EntryType][]string, len(file.DiscouragedFields))
+	}
+	for entryType, fields := range file.DiscouragedFields {
+		entryType = strings.ToLower(entryType)
+		if _, ok := profile.EntryTypes[EntryType(entryType)]; !ok {
+			return nil, &ErrInvalidProfile{Message: fmt.Sprintf("discouraged fields of unknown entry type '%s'", entryType)}
+		}
+		discouraged := make([]string, 0, len(fields))
+		for _, field := range fields {
+			if !regexProfileField.MatchString(field) {
+				return nil, &ErrInvalidProfile{Message: fmt.Sprintf("invalid discouraged field '%s' of entry type '%s'", field, entryType)}
+			}
+			field = strings.ToLower(field)
+			if profile.KnownFields != nil {
+				profile.KnownFields[field] = true
+			}
+			discouraged = append(discouraged, field)
+		}
+		profile.DiscouragedFields[EntryType(entryType)] = discouraged
+	}
+	return profile, nil
+}
+
+// fieldRule returns the FieldRule of the JSON rule with lowercased field names, or an
+// error if the rule is invalid, see LoadValidationProfile.
+func (r jsonFieldRule) fieldRule() (FieldRule, error) {
+	if len(r.Fields) == 0 {
+		return FieldRule{}, errors.New("no fields")
+	}
+	if r.Exclusive && len(r.Fields) < 2 {
+		return FieldRule{}, errors.New("an exclusive rule needs at least two fields")
+	}
+	rule := FieldRule{Fields: make([]string, 0, len(r.Fields)), Optional: r.Optional, Exclusive: r.Exclusive}
+	for _, field := range r.Fields {
+		if !regexProfileField.MatchString(field) {
+			return FieldRule{}, fmt.Errorf("invalid field '%s'", field)
+		}
+		rule.Fields = append(rule.Fields, strings.ToLower(field))
+	}
+	if r.Unless != "" && !regexProfileField.MatchString(r.Unless) {
+		return FieldRule{}, fmt.Errorf("invalid field '%s'", r.Unless)
+	}
+	rule.Unless = strings.ToLower(r.Unless)
+	return rule, nil
+}
+
+// withEntryTypes returns a copy of the rules with the additional entry types.
+func withEntryTypes(rules, additional map[EntryType][]FieldRule) map[EntryType][]FieldRule {
+	merged := maps.Clone(rules)
+	maps.Copy(merged, additional)
+	return merged
+}
+
+// biblatexDiscouragedFields returns the discouraged fields of the standard BibTeX entry
+// types with journaltitle next to journal, and of the biblatex entry types derived from them
+// (e.g., thesis from phdthesis).
+func biblatexDiscouragedFields() map[EntryType][]string {
+	discouraged := make(map[EntryType][]string, len(discouragedFields))
+	for entryType, fields := range discouragedFields {
+		if slices.Contains(fields, "journal") {
+			fields = append(slices.Clone(fields), "journaltitle")
+			slices.Sort(fields)
+		}
+		discouraged[entryType] = fields
+	}
+	for entryType, like := range map[EntryType]EntryType{
+		EntryMVBook: EntryBook, EntryBookInBook: EntryInBook, EntryCollection: EntryProceedings, EntryMVCollection: EntryProceedings,
+		EntryMVProceedings: EntryProceedings, EntryReport: EntryTechReport, EntryThesis: EntryPhDThesis,
+	} {
+		discouraged[entryType] = discouraged[like]
+	}
+	return discouraged
+}
+
+// biblatexRules returns the rules for the fields of the biblatex entry types.
+func biblatexRules() map[EntryType][]FieldRule {
+	date := requires("date", "year")
+	authored := []FieldRule{requires("author"), requires("title"), date}
+	edited := []FieldRule{requires("editor"), requires("title"), date}
+	authoredOrEdited := []FieldRule{requires("author", "editor"), requires("title"), date}
+	inBook := []FieldRule{requires("author"), requires("title"), requires("booktitle").unless("crossref"), date}
+	article := []FieldRule{requires("author"), requires("title"), requires("journaltitle", "journal").unless("crossref"), date}
+	online := []FieldRule{requires("author", "editor"), requires("title"), date, requires("doi", "eprint", "url")}
+	report := []FieldRule{requires("author"), requires("title"), requires("type"), requires("institution", "school"), date}
+	thesis := []FieldRule{requires("author"), requires("title"), requires("institution", "school"), date}
+	proceedings := []FieldRule{requires("title"), date}
+	rules := map[EntryType][]FieldRule{
+		EntryArticle: article, EntrySuppPeriodical: article,
+		EntryBook: authored, EntryMVBook: authored, EntryUnpublished: authored,
+		EntryInBook: inBook, EntryBookInBook: inBook, EntrySuppBook: inBook, EntryInCollection: inBook,
+		EntrySuppCollection: inBook, EntryInReference: inBook, EntryInProceedings: inBook,
+		EntryBooklet: authoredOrEdited, EntryDataset: authoredOrEdited, EntryManual: authoredOrEdited,
+		EntryMisc: authoredOrEdited, EntrySoftware: authoredOrEdited,
+		EntryCollection: edited, EntryMVCollection: edited, EntryReference: edited, EntryMVReference: edited, EntryPeriodical: edited,
+		EntryOnline: online, EntryPatent: {requires("author"), requires("title"), requires("number"), date},
+		EntryProceedings: proceedings, EntryMVProceedings: proceedings, EntryReport: report, EntryThesis: report,
+		// Entries that only group or share data
+		EntrySet: {requires("entryset")}, EntryXData: {},
+		// BibTeX aliases, the type is implied by mastersthesis, phdthesis, and techreport
+		EntryConference: inBook, "electronic": online, "www": online,
+		EntryMastersThesis: thesis, EntryPhDThesis: thesis, EntryTechReport: thesis,
+	}
+	// Types supported by biblatex styles without required fields
+	for _, entryType := range []EntryType{"artwork", "audio", "bibnote", "commentary", "image", "jurisdiction", "legal", "legislation", "letter", "movie", "music", "performance", "review", "standard", "video"} {
+		rules[entryType] = []FieldRule{}
+	}
+	return rules
+}
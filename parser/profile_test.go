@@ -0,0 +1,228 @@
+// Unit-tests for profile.go
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestValidationProfiles(t *testing.T) {
+	online, _ := ParseNewEntry(`@online{site, author = {Jane Doe}, title = {A Site}, date = {2020-05-01}, url = {https://example.org}, langid = {english}}`)
+	biblatex := ValidateOptions{Profile: Biblatex}
+
+	// Case 1: Biblatex types are only known by the Biblatex profile
+	if errs := online.ValidateWithOptions(context.Background(), biblatex); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+	expected := []error{&ErrUnknownEntryType{EntryType: "online", Key: "site"}}
+	if errs := online.Validate(); !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+
+	// Case 2: BibTeX field names satisfy the biblatex required fields
+	article, _ := ParseNewEntry(`@article{a, author = {Jane Doe}, title = {T}, journal = {J}, year = {2020}}`)
+	if errs := article.ValidateWithOptions(context.Background(), biblatex); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+	delete(article.Fields, "journal")
+	expected = []error{&ErrMissingField{EntryType: "article", Field: "journaltitle/journal", Key: "a"}}
+	if errs := article.ValidateWithOptions(context.Background(), biblatex); !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+
+	// Case 3: BibTeXStandard does not know the biblatex fields
+	book, _ := ParseNewEntry(`@book{b, author = {Jane Doe}, title = {T}, publisher = {P}, year = {2020}, langid = {english}, doi = {10.1000/182}}`)
+	expected = []error{&ErrUnknownField{Key: "b", Field: "langid"}}
+	if errs := book.ValidateWithOptions(context.Background(), ValidateOptions{Profile: BibTeXStandard}); !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+	if errs := book.Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+
+	// Case 4: Custom profiles without known fields accept all fields
+	custom := &ValidationProfile{Name: "custom", EntryTypes: map[EntryType][]FieldRule{EntryBook: {{Fields: []string{"isbn"}}}}}
+	expected = []error{&ErrMissingField{EntryType: "book", Field: "isbn", Key: "b"}}
+	if errs := book.ValidateWithOptions(context.Background(), ValidateOptions{Profile: custom}); !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+}
+
+func TestLookupProfile(t *testing.T) {
+	// Case 1: Built-in profiles, ignoring the case
+	if profile, ok := LookupProfile("BibLaTeX"); !ok || profile != Biblatex {
+		t.Errorf("Expected '%#v', but got '%#v'", Biblatex, profile)
+	}
+	// Case 2: Unknown profiles
+	if profile, ok := LookupProfile("acm"); ok {
+		t.Errorf("Expected no profile, but got '%#v'", profile)
+	}
+}
+
+func TestLoadValidationProfile(t *testing.T) {
+	file, err := os.Open("testdata/profile.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	profile, err := LoadValidationProfile(file)
+	if err != nil {
+		t.Fatalf("Expected no error, but got '%v'", err)
+	}
+
+	// Case 1: The rules of the file replace the rules of the base profile
+	expectedRules := []FieldRule{
+		requires("author"), requires("title"), requires("journal").unless("crossref"), requires("doi", "url"), atMostOne("volume", "number"),
+	}
+	if profile.Name != "institute" || !reflect.DeepEqual(expectedRules, profile.EntryTypes["article"]) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedRules, profile.EntryTypes["article"])
+	}
+	if !reflect.DeepEqual(DefaultProfile.EntryTypes["book"], profile.EntryTypes["book"]) {
+		t.Errorf("Expected '%#v', but got '%#v'", DefaultProfile.EntryTypes["book"], profile.EntryTypes["book"])
+	}
+	article, _ := ParseNewEntry(`@article{a, author = {Jane Doe}, title = {T}, journal = {J}, year = {2020}, projectid = {42}}`)
+	expected := []error{&ErrMissingField{EntryType: "article", Field: "doi/url", Key: "a"}}
+	if errs := article.ValidateWithOptions(context.Background(), ValidateOptions{Profile: profile}); !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+
+	// Case 2: New entry types with discouraged fields, and the known fields include the
+	// fields of the rules
+	dataset, _ := ParseNewEntry(`@dataset{d, editor = {Jane Doe}, title = {T}, repository = {Zenodo}}`)
+	if errs := dataset.ValidateWithOptions(context.Background(), ValidateOptions{Profile: profile}); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+	dataset.Fields["publisher"] = "P"
+	expected = []error{&ErrDiscouragedField{EntryType: "dataset", Field: "publisher", Key: "d"}}
+	if errs := dataset.ValidateWithOptions(context.Background(), ValidateOptions{Profile: profile}); !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+	if !reflect.DeepEqual(DefaultProfile.DiscouragedFields["book"], profile.DiscouragedFields["book"]) {
+		t.Errorf("Expected '%#v', but got '%#v'", DefaultProfile.DiscouragedFields["book"], profile.DiscouragedFields["book"])
+	}
+	if DefaultProfile.KnownFields["projectid"] || DefaultProfile.EntryTypes["dataset"] != nil || DefaultProfile.DiscouragedFields["dataset"] != nil {
+		t.Errorf("Expected the DefaultProfile to be unchanged, but got '%#v'", DefaultProfile.KnownFields)
+	}
+
+	// Case 3: Without a base profile, only the entry types of the file are known
+	profile3, err3 := LoadValidationProfile(strings.NewReader(`{"entryTypes": {"misc": []}}`))
+	if err3 != nil || profile3.Name != "custom" || len(profile3.EntryTypes) != 1 || profile3.KnownFields != nil {
+		t.Errorf("Expected a custom profile, but got '%#v' (%v)", profile3, err3)
+	}
+
+	// Case 4: Invalid profile files
+	cases := []struct {
+		json     string
+		expected string
+	}{
+		{`{"entryTypes": {"misc": []}`, "invalid JSON: unexpected EOF"},
+		{`{"rules": {"misc": []}}`, `invalid JSON: json: unknown field "rules"`},
+		{`{"extends": "default"} {}`, "unexpected data after the profile"},
+		{`{"extends": "acm"}`, "unknown base profile 'acm'"},
+		{`{"name": "empty"}`, "no entry types (and no base profile to extend)"},
+		{`{"entryTypes": {"my type": []}}`, "invalid entry type 'my type'"},
+		{`{"entryTypes": {"misc": [], "Misc": []}}`, "entry type 'misc' is defined twice"},
+		{`{"entryTypes": {"misc": [{"fields": []}]}}`, "rule 1 of entry type 'misc': no fields"},
+		{`{"entryTypes": {"misc": [{"fields": ["title"]}, {"fields": ["year"], "exclusive": true}]}}`, "rule 2 of entry type 'misc': an exclusive rule needs at least two fields"},
+		{`{"entryTypes": {"misc": [{"fields": ["2title"]}]}}`, "rule 1 of entry type 'misc': invalid field '2title'"},
+		{`{"entryTypes": {"misc": [{"fields": ["title"], "unless": "cross ref"}]}}`, "rule 1 of entry type 'misc': invalid field 'cross ref'"},
+		{`{"extends": "bibtex", "knownFields": ["a b"]}`, "invalid known field 'a b'"},
+		{`{"extends": "bibtex", "discouragedFields": {"online": ["journal"]}}`, "discouraged fields of unknown entry type 'online'"},
+		{`{"extends": "bibtex", "discouragedFields": {"book": ["journal title"]}}`, "invalid discouraged field 'journal title' of entry type 'book'"},
+	}
+	for _, c := range cases {
+		_, err := LoadValidationProfile(strings.NewReader(c.json))
+		expectedErr := &ErrInvalidProfile{Message: c.expected}
+		if !reflect.DeepEqual(expectedErr, err) {
+			t.Errorf("Expected '%v', but got '%v'", expectedErr, err)
+		}
+	}
+}
+
+func TestLoadValidationProfileYAML(t *testing.T) {
+	jsonFile, err := os.Open("testdata/profile.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jsonFile.Close()
+	yamlFile, err := os.Open("testdata/profile.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer yamlFile.Close()
+
+	// Case 1: A YAML file gives the same profile as the JSON file
+	expected, _ := LoadValidationProfile(jsonFile)
+	profile, err := LoadValidationProfile(yamlFile)
+	if err != nil || !reflect.DeepEqual(expected, profile) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", expected, profile, err)
+	}
+
+	// Case 2: Without extends, only the entry types of the file are known
+	profile2, err2 := LoadValidationProfile(strings.NewReader("entryTypes:\n  misc: []\n"))
+	if err2 != nil || profile2.Name != "custom" || len(profile2.EntryTypes) != 1 || profile2.KnownFields != nil {
+		t.Errorf("Expected a custom profile, but got '%#v' (%v)", profile2, err2)
+	}
+
+	// Case 3: Invalid YAML files are checked like JSON files
+	cases := []struct {
+		yaml     string
+		expected string
+	}{
+		{"rules:\n  misc: []\n", "invalid YAML: yaml: unmarshal errors:\n  line 1: field rules not found in type parser.jsonProfile"},
+		{"extends: default\n---\nextends: bibtex\n", "unexpected data after the profile"},
+		{"extends: acm\n", "unknown base profile 'acm'"},
+		{"entryTypes:\n  misc:\n    - fields: []\n", "rule 1 of entry type 'misc': no fields"},
+		{"", "invalid YAML: EOF"},
+	}
+	for _, c := range cases {
+		_, err := LoadValidationProfile(strings.NewReader(c.yaml))
+		expectedErr := &ErrInvalidProfile{Message: c.expected}
+		if !reflect.DeepEqual(expectedErr, err) {
+			t.Errorf("Expected '%v', but got '%v'", expectedErr, err)
+		}
+	}
+}
+
+func TestProfileSchema(t *testing.T) {
+	data, err := os.ReadFile("profile.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+		Defs       struct {
+			FieldRule struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"fieldRule"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Expected a valid JSON schema, but got '%v'", err)
+	}
+
+	// Case 1: The schema describes the properties read by LoadValidationProfile
+	for _, c := range []struct {
+		properties map[string]any
+		value      any
+	}{{schema.Properties, jsonProfile{}}, {schema.Defs.FieldRule.Properties, jsonFieldRule{}}} {
+		var expected, got []string
+		valueType := reflect.TypeOf(c.value)
+		for i := range valueType.NumField() {
+			expected = append(expected, valueType.Field(i).Tag.Get("json"))
+		}
+		for property := range c.properties {
+			got = append(got, property)
+		}
+		sort.Strings(expected)
+		sort.Strings(got)
+		if !reflect.DeepEqual(expected, got) {
+			t.Errorf("Expected '%#v', but got '%#v'", expected, got)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// The rename.go source file includes functions to rename the keys of BibTeX entries
+//
+// RenameKey: changes the key of an entry and all references to it
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Define errors
+type ErrKeyNotFound struct {
+	Key string
+}
+
+func (e *ErrKeyNotFound) Error() string {
+	return fmt.Sprintf("Error renaming BibTeX entry: key '%s' does not exist", e.Key)
+}
+
+type ErrKeyExists struct {
+	Key string
+}
+
+func (e *ErrKeyExists) Error() string {
+	return fmt.Sprintf("Error renaming BibTeX entry: key '%s' already exists", e.Key)
+}
+
+// keyReferenceFields are the fields that reference other entries by their key. The fields
+// mapped to true contain a comma-separated list of keys (e.g., entryset = {a, b}).
+var keyReferenceFields = map[string]bool{
+	"crossref": false, "xref": false, "entryset": true, "xdata": true, "related": true,
+}
+
+// RenameKey changes the key of the entry with the key old to new and updates all fields of
+// other entries that reference old (crossref, xref, entryset, xdata, and related), so the
+// references stay consistent. If several entries share the key old, only the first one is
+// renamed (see LookupByKey), but all references are changed.
+// RenameKey returns an ErrKeyNotFound if old does not exist, an ErrKeyExists if new is
+// already used by another entry, and an ErrInvalidKey if new contains invalid chars.
+// Nothing is changed if an error is returned.
+func (f *BibTeXFile) RenameKey(old, new string) error {
+	entry, ok := f.LookupByKey(old)
+	if !ok {
+		return &ErrKeyNotFound{Key: old}
+	}
+	if old == new {
+		return nil
+	}
+	if new == "" {
+		return &ErrEmptyString{Message: "The new key is empty."}
+	}
+	if err := validateKey(new); err != nil {
+		return err
+	}
+	if _, ok := f.LookupByKey(new); ok {
+		return &ErrKeyExists{Key: new}
+	}
+	entry.Key = new
+	f.replaceKeyReferences(old, new)
+	// The lookup index still maps the old key
+	f.keyIndex = nil
+	return nil
+}
+
+// replaceKeyReferences replaces the key old by new in the fields of all entries that
+// reference other entries, see keyReferenceFields.
+func (f *BibTeXFile) replaceKeyReferences(old, new string) {
+	for _, entry := range f.Entries {
+		for field, isList := range keyReferenceFields {
+			value, ok := entry.Fields[field]
+			if !ok {
+				continue
+			}
+			if isList {
+				entry.Fields[field] = renameInKeyList(value, old, new)
+			} else if strings.TrimSpace(value) == old {
+				entry.Fields[field] = new
+			}
+		}
+	}
+}
+
+// renameInKeyList replaces the key old by new in the comma-separated list of keys. If the
+// list already contains new, old is removed instead, so no key is listed twice. The list
+// is returned unchanged if it does not contain old.
+func renameInKeyList(list, old, new string) string {
+	keys := strings.Split(list, ",")
+	hasNew := false
+	for _, key := range keys {
+		hasNew = hasNew || strings.TrimSpace(key) == new
+	}
+	renamed := make([]string, 0, len(keys))
+	changed := false
+	for _, key := range keys {
+		switch {
+		case strings.TrimSpace(key) != old:
+			renamed = append(renamed, key)
+		case hasNew:
+			changed = true
+		default:
+			renamed = append(renamed, strings.Replace(key, old, new, 1))
+			changed = true
+		}
+	}
+	if !changed {
+		return list
+	}
+	return strings.Join(renamed, ",")
+}
@@ -0,0 +1,72 @@
+// Unit-tests for rename.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenameKey(t *testing.T) {
+	file, _ := ParseString(`@book{old, title = {Parent}, publisher = {P}, year = {2020}}
+@inbook{chapter, crossref = {old}, title = {Chapter}, pages = {1--10}}
+@set{set1, entryset = {first, old,older}}
+@misc{note1, xref = {old}, xdata = {older}, related = {old}}
+@misc{first, title = {First}}
+@misc{older, title = {Older}}`)
+
+	// Case 1: The key and all references are renamed, similar keys are kept
+	if err := file.RenameKey("old", "new2020"); err != nil {
+		t.Errorf("Expected no error, but got '%#v'", err)
+	}
+	expectedKeys := []string{"new2020", "chapter", "set1", "note1", "first", "older"}
+	if keys := entryKeys(file.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+	expected := map[string]string{
+		"crossref": "new2020",
+		"entryset": "first, new2020,older",
+		"xref":     "new2020",
+		"xdata":    "older",
+		"related":  "new2020",
+	}
+	got := map[string]string{
+		"crossref": file.Entries[1].Fields["crossref"],
+		"entryset": file.Entries[2].Fields["entryset"],
+		"xref":     file.Entries[3].Fields["xref"],
+		"xdata":    file.Entries[3].Fields["xdata"],
+		"related":  file.Entries[3].Fields["related"],
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, got)
+	}
+
+	// Case 2: The lookup index follows the new key
+	if _, ok := file.LookupByKey("old"); ok {
+		t.Errorf("Expected '%#v' to be renamed", "old")
+	}
+	if entry, ok := file.LookupByKey("new2020"); !ok || entry.Fields["title"] != "Parent" {
+		t.Errorf("Expected '%#v', but got '%#v'", "Parent", entry)
+	}
+	if errs := file.ResolveCrossrefs(); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+
+	// Case 3: Existing, missing, and invalid keys are errors and change nothing
+	cases := []struct {
+		old, new string
+		expected error
+	}{
+		{"new2020", "first", &ErrKeyExists{Key: "first"}},
+		{"missing", "other", &ErrKeyNotFound{Key: "missing"}},
+		{"first", "my key", &ErrInvalidKey{Key: "my key", BadChar: ' '}},
+		{"first", "", &ErrEmptyString{Message: "The new key is empty."}},
+	}
+	for _, c := range cases {
+		if err := file.RenameKey(c.old, c.new); !reflect.DeepEqual(c.expected, err) {
+			t.Errorf("Expected '%#v', but got '%#v'", c.expected, err)
+		}
+	}
+	if keys := entryKeys(file.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+}
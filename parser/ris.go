@@ -0,0 +1,166 @@
+// The ris.go source file includes functions to export BibTeX entries in the RIS format
+//
+// MarshalRIS: exports all entries of a BibTeXFile as RIS records
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// risTypes maps BibTeX entry types to RIS reference types.
+// Unknown entry types are exported as GEN.
+var risTypes = map[EntryType]string{
+	EntryArticle:       "JOUR",
+	EntryBook:          "BOOK",
+	EntryBooklet:       "PAMP",
+	EntryConference:    "CPAPER",
+	EntryInBook:        "CHAP",
+	EntryInCollection:  "CHAP",
+	EntryInProceedings: "CPAPER",
+	EntryManual:        "GEN",
+	EntryMastersThesis: "THES",
+	EntryMisc:          "GEN",
+	EntryOnline:        "ELEC",
+	EntryPhDThesis:     "THES",
+	EntryProceedings:   "CONF",
+	EntryTechReport:    "RPRT",
+	EntryUnpublished:   "UNPUB",
+}
+
+// risTags maps BibTeX fields to RIS tags.
+// The fields author, editor, year, month, date, pages, and keywords are handled separately.
+var risTags = map[string]string{
+	"abstract":     "AB",
+	"address":      "CY",
+	"booktitle":    "T2",
+	"doi":          "DO",
+	"edition":      "ET",
+	"institution":  "PB",
+	"isbn":         "SN",
+	"issn":         "SN",
+	"journal":      "JO",
+	"language":     "LA",
+	"note":         "N1",
+	"number":       "IS",
+	"organization": "PB",
+	"publisher":    "PB",
+	"school":       "PB",
+	"series":       "T3",
+	"title":        "TI",
+	"url":          "UR",
+	"volume":       "VL",
+}
+
+// MarshalRIS exports all entries except for @xdata entries as RIS records (e.g., for
+// EndNote or Zotero).
+// Each record starts with the TY line of the mapped entry type, followed by the key
+// as ID, and ends with an ER line. Lines are separated by CRLF as defined by RIS.
+//
+// The conversion is lossy:
+//   - Braces protecting the capitalization are removed from all values.
+//   - The author and editor fields are split into one AU/ED line per name (Last, First, Jr).
+//   - The year becomes PY; the month is only kept if a year is given (DA: year/month//).
+//   - The pages are split into SP and EP, see Entry.Pages.
+//   - The keywords are split into one KW line per keyword on ',' or ';'.
+//   - publisher, school, institution, and organization all become PB; isbn and issn both become SN.
+//   - All other fields (e.g., chapter, crossref, howpublished) have no RIS tag and are dropped.
+//
+// Dropped fields do not fail the export: the RIS records are returned together with
+// an error joining an ErrUnmappedField for every dropped field.
+func (f *BibTeXFile) MarshalRIS() ([]byte, error) {
+	var builder strings.Builder
+	var warnings []error
+	for _, entry := range f.Entries {
+		if entry.isPrinted() {
+			warnings = append(warnings, entry.writeRIS(&builder)...)
+		}
+	}
+	return []byte(builder.String()), errors.Join(warnings...)
+}
+
+// writeRIS writes the entry as one RIS record and returns the warnings for all dropped fields.
+func (e *Entry) writeRIS(builder *strings.Builder) []error {
+	writeLine := func(tag, value string) {
+		builder.WriteString(tag + "  - " + value + "\r\n")
+	}
+	entryType, _ := e.TypedType()
+	risType, ok := risTypes[entryType]
+	if !ok {
+		risType = "GEN"
+	}
+	writeLine("TY", risType)
+	writeLine("ID", e.Key)
+	var warnings []error
+	for _, fieldName := range e.fieldOrder() {
+		value := strings.TrimSpace(stripBraces(e.Fields[fieldName]))
+		switch fieldName {
+		case "author", "editor":
+			tag := "AU"
+			if fieldName == "editor" {
+				tag = "ED"
+			}
+			for _, name := range SplitNames(e.Fields[fieldName]) {
+				writeLine(tag, risName(name))
+			}
+		case "year":
+			writeLine("PY", value)
+			if month, ok := e.monthNumber(); ok {
+				writeLine("DA", fmt.Sprintf("%s/%02d//", value, month))
+			}
+		case "month":
+			// Handled with the year
+		case "date":
+			if _, ok := e.Fields["year"]; !ok {
+				if match := regexISODate.FindStringSubmatch(value); match != nil {
+					writeLine("PY", match[1])
+				} else {
+					writeLine("PY", value)
+				}
+			}
+		case "pages":
+			if startPage, endPage, ok := e.Pages(); ok {
+				writeLine("SP", startPage)
+				if endPage != startPage {
+					writeLine("EP", endPage)
+				}
+			}
+		case "keywords":
+			for _, keyword := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+				if keyword = strings.TrimSpace(keyword); keyword != "" {
+					writeLine("KW", keyword)
+				}
+			}
+		default:
+			tag, ok := risTags[fieldName]
+			if !ok {
+				warnings = append(warnings, &ErrUnmappedField{Key: e.Key, Field: fieldName, Format: "RIS"})
+				continue
+			}
+			writeLine(tag, value)
+		}
+	}
+	writeLine("ER", "")
+	return warnings
+}
+
+// risName formats a BibTeX name as "Last, First, Jr" for the AU and ED lines.
+// Corporate names (e.g., {Barnes and Noble}) are kept as they are.
+func risName(name string) string {
+	if isCorporateName(name) {
+		return stripBraces(name)
+	}
+	first, von, last, jr := ParseName(name)
+	parts := []string{strings.TrimSpace(von + " " + last)}
+	if first != "" || jr != "" {
+		parts = append(parts, first)
+	}
+	if jr != "" {
+		parts = append(parts, jr)
+	}
+	return stripBraces(strings.Join(parts, ", "))
+}
@@ -0,0 +1,69 @@
+// Unit-tests for ris.go
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMarshalRIS(t *testing.T) {
+	// Case 1: Article with several authors, pages, and keywords
+	bibtexFile1, _ := ParseNewBibTeXFile(strings.NewReader(`@article{muster2024,
+  author   = {Mustermann, Max and Erika von Musterfrau and {Barnes and Noble}},
+  title    = {Einführung in die {D}atenwissenschaft},
+  journal  = {Journal für Informatik},
+  pages    = {10--20},
+  year     = {2024},
+  month    = mar,
+  keywords = {daten, informatik}
+}`))
+	expected1 := "TY  - JOUR\r\n" +
+		"ID  - muster2024\r\n" +
+		"AU  - Mustermann, Max\r\n" +
+		"AU  - von Musterfrau, Erika\r\n" +
+		"AU  - Barnes and Noble\r\n" +
+		"TI  - Einführung in die Datenwissenschaft\r\n" +
+		"JO  - Journal für Informatik\r\n" +
+		"PY  - 2024\r\n" +
+		"DA  - 2024/03//\r\n" +
+		"KW  - daten\r\n" +
+		"KW  - informatik\r\n" +
+		"SP  - 10\r\n" +
+		"EP  - 20\r\n" +
+		"ER  - \r\n"
+	data1, err1 := bibtexFile1.MarshalRIS()
+	if err1 != nil {
+		t.Errorf("Expected no error, but got '%#v'", err1)
+	}
+	if string(data1) != expected1 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, string(data1))
+	}
+
+	// Case 2: Dropped fields are reported
+	bibtexFile2, _ := ParseNewBibTeXFile(strings.NewReader(`@inbook{weber2020, editor = {Weber, Eva}, title = {Kapitel}, chapter = {3}, publisher = {Technik Verlag}, year = {2020}}`))
+	expected2 := "TY  - CHAP\r\n" +
+		"ID  - weber2020\r\n" +
+		"ED  - Weber, Eva\r\n" +
+		"TI  - Kapitel\r\n" +
+		"PB  - Technik Verlag\r\n" +
+		"PY  - 2020\r\n" +
+		"ER  - \r\n"
+	data2, err2 := bibtexFile2.MarshalRIS()
+	if string(data2) != expected2 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, string(data2))
+	}
+	var unmapped *ErrUnmappedField
+	if !errors.As(err2, &unmapped) || unmapped.Field != "chapter" || unmapped.Format != "RIS" {
+		t.Errorf("Expected '%#v', but got '%#v'", &ErrUnmappedField{Key: "weber2020", Field: "chapter", Format: "RIS"}, err2)
+	}
+}
+
+func TestRISName(t *testing.T) {
+	// Case 1: Name with a suffix
+	expected1 := "Ford, Henry, Jr."
+	result1 := risName("Ford, Jr., Henry")
+	if expected1 != result1 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, result1)
+	}
+}
@@ -0,0 +1,294 @@
+// The scanner.go source file includes a scanner which splits BibTeX input into raw entries
+//
+// EntryScanner: reads raw BibTeX entries one at a time from an io.Reader
+// SplitEntries: splits BibTeX input into the raw texts of all entries
+// CheckStrayText: reports the text between the entries of a BibTeXFile
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Define errors
+type ErrStrayText struct {
+	Text string
+	Line int // The line where the text begins in the BibTeX file.
+}
+
+func (e *ErrStrayText) Error() string {
+	return fmt.Sprintf("Warning parsing BibTeX file: text outside of entries, e.g., a half-deleted entry (line %d): %s", e.Line, truncateRunes(strings.Join(strings.Fields(e.Text), " "), 40))
+}
+
+// StrayText is text between the entries of a BibTeX file that is neither white space nor a
+// % comment, e.g., editor artifacts or the remains of a half-deleted entry. BibTeX ignores
+// it, but it often points to a broken entry, see CheckStrayText.
+type StrayText struct {
+	Text       string // The text, trimmed.
+	Line       int    // The line where the text begins in the parsed input.
+	Offset     int    // The byte offset where the text begins in the parsed input.
+	SourceFile string // The path of the BibTeX file of the text (set by ParseFiles).
+}
+
+// TeXComment is a % comment line between the entries of a BibTeX file, which BibTeX ignores.
+type TeXComment struct {
+	Text string // The comment including its '%', without the line break.
+	Line int    // The line of the comment in the parsed input.
+}
+
+// EntryScanner reads raw BibTeX entries from an io.Reader without loading the whole input.
+// An entry starts with an '@' at brace depth zero and ends with the '}' that closes its body
+// (or the ')' at brace depth zero if the body is opened with '(', e.g., @article(id, ...)),
+// so '@' characters inside field values (e.g., in URLs or e-mail addresses) do not split an entry.
+// Text between entries, such as % comments and the indentation of entries (the '@' does not
+// have to start a line), is skipped, as well as a UTF-8 byte order mark at the start of the
+// input. The % comments are returned by TeXComments and other text between entries by StrayText.
+//
+// Usage:
+//
+//	scanner := NewEntryScanner(r)
+//	for scanner.Scan() {
+//		entry, err := ParseNewEntry(scanner.Text())
+//	}
+//	if err := scanner.Err(); err != nil {
+//		...
+//	}
+type EntryScanner struct {
+	reader      *bufio.Reader
+	text        string
+	err         error
+	done        bool
+	currentLine int // The line number of the reader position.
+	entryLine   int // The line number where the current entry starts.
+	offset      int // The byte offset of the reader position.
+	entryStart  int // The byte offset where the current entry starts.
+	entryEnd    int // The byte offset after the current entry.
+	stray       []StrayText
+	comments    []TeXComment
+	strayText   strings.Builder // The stray text that is currently read.
+	strayStart  StrayText       // The position of the stray text that is currently read.
+}
+
+// The UTF-8 byte order mark written at the start of files by some editors
+const byteOrderMark = "\ufeff"
+
+// NewEntryScanner returns a new EntryScanner reading from r.
+func NewEntryScanner(r io.Reader) *EntryScanner {
+	return &EntryScanner{reader: bufio.NewReader(r), currentLine: 1}
+}
+
+// Scan advances the scanner to the next raw entry, which is then available via Text.
+// It returns false when the end of the input is reached or an error occurs.
+// An unterminated entry at the end of the input is still returned.
+func (s *EntryScanner) Scan() bool {
+	s.text = ""
+	s.entryLine = 0
+	s.entryStart = s.offset
+	s.entryEnd = s.offset
+	s.stray = nil
+	s.comments = nil
+	if s.done {
+		return false
+	}
+	if s.offset == 0 {
+		s.skipByteOrderMark()
+	}
+	var builder strings.Builder
+	inEntry := false
+	opened := false
+	escaped := false
+	depth := 0
+	// The delimiter closing the entry body, either '}' or ')'
+	closing := '}'
+	for {
+		char, size, err := s.reader.ReadRune()
+		s.offset += size
+		if err != nil {
+			s.done = true
+			if err != io.EOF {
+				s.err = err
+				return false
+			}
+			s.endStrayText()
+			// Return remaining (unterminated) entry
+			if inEntry {
+				s.text = builder.String()
+				s.entryEnd = s.offset
+				return true
+			}
+			return false
+		}
+		// Skip everything outside of entries
+		if !inEntry {
+			switch {
+			case char == '@':
+				s.endStrayText()
+				inEntry = true
+				s.entryLine = s.currentLine
+				s.entryStart = s.offset - size
+				builder.WriteRune(char)
+			case char == '%':
+				s.endStrayText()
+				// Skip comment line, errors are handled by the next ReadRune
+				line, err := s.reader.ReadString('\n')
+				s.offset += len(line)
+				s.comments = append(s.comments, TeXComment{Text: "%" + strings.TrimRight(line, "\r\n"), Line: s.currentLine})
+				if err == nil {
+					s.currentLine++
+				}
+			case char == '\n':
+				s.currentLine++
+				s.strayText.WriteRune(char)
+			case isEntrySpace(char):
+				s.strayText.WriteRune(char)
+			default:
+				if strings.TrimFunc(s.strayText.String(), isEntrySpace) == "" {
+					// The stray text starts here, after the white space before it
+					s.strayText.Reset()
+					s.strayStart = StrayText{Line: s.currentLine, Offset: s.offset - size}
+				}
+				s.strayText.WriteRune(char)
+			}
+			continue
+		}
+		// An '@' before the entry body has been opened starts a new entry,
+		// e.g., "@misc @article{...}"
+		if char == '@' && !opened {
+			_ = s.reader.UnreadRune()
+			s.offset -= size
+			s.text = builder.String()
+			s.entryEnd = s.offset
+			return true
+		}
+		if char == '\n' {
+			s.currentLine++
+		}
+		builder.WriteRune(char)
+		// Escaped braces like \{ do not change the depth
+		if escaped {
+			escaped = false
+			continue
+		}
+		switch char {
+		case '\\':
+			escaped = true
+		case '{':
+			depth++
+			opened = true
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+			if opened && closing == '}' && depth == 0 {
+				s.text = builder.String()
+				s.entryEnd = s.offset
+				return true
+			}
+		case '(':
+			if !opened {
+				opened = true
+				closing = ')'
+			}
+		case ')':
+			if opened && closing == ')' && depth == 0 {
+				s.text = builder.String()
+				s.entryEnd = s.offset
+				return true
+			}
+		}
+	}
+}
+
+// endStrayText adds the stray text that is currently read (if any) to the stray texts
+// returned by StrayText.
+func (s *EntryScanner) endStrayText() {
+	if text := strings.TrimFunc(s.strayText.String(), isEntrySpace); text != "" {
+		stray := s.strayStart
+		stray.Text = text
+		s.stray = append(s.stray, stray)
+	}
+	s.strayText.Reset()
+}
+
+// skipByteOrderMark skips a UTF-8 byte order mark at the start of the input. The
+// offsets of the entries still count its bytes, so they match the input.
+func (s *EntryScanner) skipByteOrderMark() {
+	prefix, err := s.reader.Peek(len(byteOrderMark))
+	if string(prefix) == byteOrderMark {
+		s.offset, _ = s.reader.Discard(len(byteOrderMark))
+	} else if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		s.err = err
+		s.done = true
+	}
+}
+
+// Text returns the raw entry found by the last call to Scan.
+func (s *EntryScanner) Text() string {
+	return s.text
+}
+
+// Line returns the line number (starting with 1) where the entry returned by Text begins.
+func (s *EntryScanner) Line() int {
+	return s.entryLine
+}
+
+// Offsets returns the byte offsets in the input where the entry returned by Text begins
+// (at its '@') and ends (after its closing delimiter), so input[start:end] is the raw entry.
+func (s *EntryScanner) Offsets() (start, end int) {
+	return s.entryStart, s.entryEnd
+}
+
+// StrayText returns the text between the previous entry and the entry returned by Text
+// (or the end of the input, once Scan returns false) that is neither white space nor a %
+// comment, see StrayText. It returns nil if there is no such text.
+func (s *EntryScanner) StrayText() []StrayText {
+	return s.stray
+}
+
+// TeXComments returns the % comments between the previous entry and the entry returned
+// by Text (or the end of the input, once Scan returns false). It returns nil if there are none.
+func (s *EntryScanner) TeXComments() []TeXComment {
+	return s.comments
+}
+
+// Err returns the first non-EOF error encountered by the scanner.
+func (s *EntryScanner) Err() error {
+	return s.err
+}
+
+// SplitEntries reads the whole input and returns the raw text of each top-level entry,
+// including @string, @comment, and @preamble blocks, without parsing them. The input is
+// split like by an EntryScanner, so '@' characters inside field values do not start a
+// new entry. The raw texts can be parsed with ParseNewEntry, e.g., in parallel.
+// Note that @string macros are not resolved when the entries are parsed on their own.
+func SplitEntries(r io.Reader) ([]string, error) {
+	var entries []string
+	scanner := NewEntryScanner(r)
+	for scanner.Scan() {
+		entries = append(entries, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CheckStrayText returns one ErrStrayText warning per text between the entries of the file
+// (see StrayText), which is often the remains of a broken or half-deleted entry. The warnings
+// of texts from ParseFiles are wrapped in an ErrInFile with their path.
+func (f *BibTeXFile) CheckStrayText() []error {
+	var errs []error
+	for _, stray := range f.StrayText {
+		var err error = &ErrStrayText{Text: stray.Text, Line: stray.Line}
+		if stray.SourceFile != "" {
+			err = &ErrInFile{Path: stray.SourceFile, Err: err}
+		}
+		errs = append(errs, err)
+	}
+	return errs
+}
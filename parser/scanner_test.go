@@ -0,0 +1,208 @@
+// Unit-tests for scanner.go
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEntryScanner(t *testing.T) {
+	bib := `% Comment with an @ that should not start an entry
+@misc{doe2021,
+  url          = {http://example.com/@user},
+  howpublished = {Mail to doe@example.com}
+}
+Some junk between entries
+  @book{knuth1997art, title = {{The Art} of Computer Programming}}
+@article{unclosed, title = {Unclosed}`
+
+	// Case 1: Split entries on '@' at brace depth zero only
+	expected := []string{
+		`@misc{doe2021,
+  url          = {http://example.com/@user},
+  howpublished = {Mail to doe@example.com}
+}`,
+		`@book{knuth1997art, title = {{The Art} of Computer Programming}}`,
+		`@article{unclosed, title = {Unclosed}`,
+	}
+	scanner := NewEntryScanner(strings.NewReader(bib))
+	var rawEntries []string
+	for scanner.Scan() {
+		rawEntries = append(rawEntries, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("Expected no error, but got '%#v'", err)
+	}
+	if !reflect.DeepEqual(expected, rawEntries) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, rawEntries)
+	}
+
+	// Case 2: Parse entries from the scanner
+	scanner2 := NewEntryScanner(strings.NewReader(bib))
+	scanner2.Scan()
+	entry, _ := ParseNewEntry(scanner2.Text())
+	expectedURL := "http://example.com/@user"
+	if entry.Fields["url"] != expectedURL {
+		t.Errorf("Expected '%s', but got '%s'", expectedURL, entry.Fields["url"])
+	}
+
+	// Case 3: Empty input
+	scanner3 := NewEntryScanner(strings.NewReader(""))
+	if scanner3.Scan() {
+		t.Errorf("Expected no entry, but got '%s'", scanner3.Text())
+	}
+
+	// Case 4: Escaped braces do not end the entry
+	bib4 := `@misc{a, title = {x \} y}}
+@misc{b, title = {B}}`
+	expected4 := []string{`@misc{a, title = {x \} y}}`, `@misc{b, title = {B}}`}
+	scanner4 := NewEntryScanner(strings.NewReader(bib4))
+	var rawEntries4 []string
+	for scanner4.Scan() {
+		rawEntries4 = append(rawEntries4, scanner4.Text())
+	}
+	if !reflect.DeepEqual(expected4, rawEntries4) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected4, rawEntries4)
+	}
+}
+
+func TestEntryScannerLine(t *testing.T) {
+	bib := `% Comment
+
+@misc{doe2021,
+  title = {First}
+}
+% Comment
+@misc{smith2020, title = {Second}} @misc{roe2022,
+  title = {Third}}`
+
+	expected := []int{3, 7, 7}
+	scanner := NewEntryScanner(strings.NewReader(bib))
+	var lines []int
+	for scanner.Scan() {
+		lines = append(lines, scanner.Line())
+	}
+	if !reflect.DeepEqual(expected, lines) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, lines)
+	}
+}
+
+func TestEntryScannerOffsets(t *testing.T) {
+	bib := "% Kommentar über @misc\n@misc{müller, title = {Größe}}\n junk @misc @book{b, title = {B}}\n@article(c, title = {C})\n@misc{unclosed, title = {Ä}"
+
+	// Case 1: The offsets of each entry select its raw text from the input, also after
+	// multi-byte characters, comments, and an '@' before the entry body
+	scanner := NewEntryScanner(strings.NewReader(bib))
+	var starts []int
+	for scanner.Scan() {
+		start, end := scanner.Offsets()
+		if bib[start:end] != scanner.Text() {
+			t.Errorf("Expected '%#v', but got '%#v'", scanner.Text(), bib[start:end])
+		}
+		starts = append(starts, start)
+	}
+	expected := []int{24, 64, 70, 92, 117}
+	if !reflect.DeepEqual(expected, starts) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, starts)
+	}
+}
+
+func TestSplitEntries(t *testing.T) {
+	// Case 1: '@' inside of values does not split an entry
+	bib := `@string{me = {doe@example.com}}
+@misc{doe2021, howpublished = {Mail to {doe@example.com}}}
+@comment{Exported}`
+	expected := []string{
+		`@string{me = {doe@example.com}}`,
+		`@misc{doe2021, howpublished = {Mail to {doe@example.com}}}`,
+		`@comment{Exported}`,
+	}
+	entries, err := SplitEntries(strings.NewReader(bib))
+	if err != nil || !reflect.DeepEqual(expected, entries) {
+		t.Errorf("Expected '%#v', but got '%#v' (%v)", expected, entries, err)
+	}
+
+	// Case 2: Empty input
+	if entries2, err2 := SplitEntries(strings.NewReader("")); err2 != nil || len(entries2) != 0 {
+		t.Errorf("Expected no entries, but got '%#v' (%v)", entries2, err2)
+	}
+}
+
+func TestEntryScannerStrayText(t *testing.T) {
+	bib := "% Comment\n  \n@misc{a, title = {A}}\n junk }\n\t@misc{b}\n% Comment\nmore\n"
+
+	// Case 1: The stray text before each entry and at the end of the input with its position,
+	// but not the white space and % comments
+	scanner := NewEntryScanner(strings.NewReader(bib))
+	var stray [][]StrayText
+	for scanner.Scan() {
+		stray = append(stray, scanner.StrayText())
+	}
+	stray = append(stray, scanner.StrayText())
+	expected := [][]StrayText{
+		nil,
+		{{Text: "junk }", Line: 4, Offset: 36}},
+		{{Text: "more", Line: 7, Offset: 63}},
+	}
+	if !reflect.DeepEqual(expected, stray) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, stray)
+	}
+	if bib[36:42] != "junk }" || bib[63:67] != "more" {
+		t.Errorf("Expected the offsets of the stray text, but got '%#v'", stray)
+	}
+
+	// Case 2: The % comments are returned separately with their lines
+	scanner2 := NewEntryScanner(strings.NewReader(bib))
+	var comments []TeXComment
+	for scanner2.Scan() {
+		comments = append(comments, scanner2.TeXComments()...)
+	}
+	comments = append(comments, scanner2.TeXComments()...)
+	expected2 := []TeXComment{{Text: "% Comment", Line: 1}, {Text: "% Comment", Line: 6}}
+	if !reflect.DeepEqual(expected2, comments) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, comments)
+	}
+}
+
+func TestCheckStrayText(t *testing.T) {
+	// Case 1: Garbage between two valid entries is reported, but does not break the entries
+	bibtexFile, errs := ParseFiles("testdata/stray.bib")
+	if len(errs) != 0 || len(bibtexFile.Errors) != 0 {
+		t.Errorf("Expected no errors, but got '%#v' and '%#v'", errs, bibtexFile.Errors)
+	}
+	expectedKeys := []string{"first2024", "second2023"}
+	if keys := entryKeys(bibtexFile.Entries); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+	problems := bibtexFile.CheckStrayText()
+	expectedLines := []int{10, 21}
+	if len(problems) != len(expectedLines) {
+		t.Fatalf("Expected %d warnings, but got '%#v'", len(expectedLines), problems)
+	}
+	for i, problem := range problems {
+		var strayErr *ErrStrayText
+		var fileErr *ErrInFile
+		if !errors.As(problem, &strayErr) || !errors.As(problem, &fileErr) || !IsWarning(problem) || strayErr.Line != expectedLines[i] || fileErr.Path != "testdata/stray.bib" {
+			t.Errorf("Expected a warning in line %d, but got '%#v'", expectedLines[i], problem)
+		}
+	}
+	if !strings.HasPrefix(bibtexFile.StrayText[0].Text, "title = {Remains") || !strings.HasSuffix(bibtexFile.StrayText[0].Text, "<<<<<<< HEAD") {
+		t.Errorf("Expected the remains of the deleted entry, but got '%#v'", bibtexFile.StrayText[0].Text)
+	}
+
+	// Case 2: The message shows the first 40 characters of the text on one line
+	err := &ErrStrayText{Text: "title = {A},\n  year = {2020}\n}\n<<<<<<< HEAD\n\n>>>>>>> main", Line: 10}
+	expected := "Warning parsing BibTeX file: text outside of entries, e.g., a half-deleted entry (line 10): title = {A}, year = {2020} } <<<<<<< HEA..."
+	if err.Error() != expected {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, err.Error())
+	}
+
+	// Case 3: Files without stray text
+	for _, path := range []string{"../bibliography.bib", "testdata/indented.bib", "testdata/bom.bib"} {
+		if bibtexFile2, _ := ParseFiles(path); len(bibtexFile2.CheckStrayText()) != 0 {
+			t.Errorf("Expected no stray text in '%s', but got '%#v'", path, bibtexFile2.StrayText)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// The search.go source file includes functions to search BibTeX entries independent of accents
+//
+// SearchText: returns the lowercased, accent-folded author, title, and year of an Entry
+// Search: returns the entries of a BibTeXFile matching all terms of a query
+// foldASCII: transliterates accented and special letters to ASCII
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// searchFields are the fields included in the SearchText of an entry.
+var searchFields = []string{"author", "title", "year"}
+
+// asciiLetters maps the letters without a decomposition to their ASCII transliteration.
+var asciiLetters = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe", 'ø': "o", 'Ø': "o",
+	'ł': "l", 'Ł': "l", 'đ': "d", 'Đ': "d", 'ð': "d", 'þ': "th", 'ı': "i", 'ȷ': "j",
+}
+
+// SearchText returns the author, title, and year of the entry as one lowercased string
+// without diacritics, e.g., "müller, jörg the {LaTeX} book 2020" becomes
+// "muller, jorg the latex book 2020", so that searching for "Muller" finds "Müller".
+// LaTeX commands (e.g., M\"uller) are decoded and braces are removed before folding.
+func (e *Entry) SearchText() string {
+	var parts []string
+	for _, fieldName := range searchFields {
+		if value, ok := e.Fields[fieldName]; ok {
+			parts = append(parts, foldASCII(stripBraces(DecodeLaTeX(value))))
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+// Search returns the entries whose SearchText contains all white-space-separated
+// terms of the query, e.g., "muller 2020" finds the entries of Müller from 2020.
+// The terms are matched case- and accent-insensitively as substrings, in the order of
+// the entries. An empty query matches all entries.
+func (f *BibTeXFile) Search(query string) []*Entry {
+	terms := strings.Fields(foldASCII(query))
+	var matches []*Entry
+	for _, entry := range f.Entries {
+		text := entry.SearchText()
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// foldASCII lowercases the string and removes the diacritics of all letters (e.g., é to e),
+// transliterating special letters like ß to ss.
+func foldASCII(s string) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if letters, ok := asciiLetters[r]; ok {
+			builder.WriteString(letters)
+			continue
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
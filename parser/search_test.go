@@ -0,0 +1,53 @@
+// Unit-tests for search.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchText(t *testing.T) {
+	// Case 1: Accents, LaTeX commands, and braces are folded
+	entry, _ := ParseNewEntry(`@book{a, author = {M\"uller, J{\"o}rg and Øster, Łukasz}, title = {Die {Straße} der {Crème Brûlée}}, year = {2020}, publisher = {Verlag}}`)
+	expected := "muller, jorg and oster, lukasz die strasse der creme brulee 2020"
+	if text := entry.SearchText(); expected != text {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, text)
+	}
+
+	// Case 2: Missing fields are skipped
+	entry, _ = ParseNewEntry(`@misc{b, title = {Çà  et là}}`)
+	if text := entry.SearchText(); text != "ca et la" {
+		t.Errorf("Expected '%#v', but got '%#v'", "ca et la", text)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	file, _ := ParseString(`@book{a, author = {Müller, Jörg}, title = {Datenanalyse}, year = {2020}, publisher = {P}}
+@book{b, author = {M\"uller, Anna}, title = {Statistik}, year = {2021}, publisher = {P}}
+@book{c, author = {Weber, Eva}, title = {Datenbanken}, year = {2020}, publisher = {P}}`)
+	keys := func(entries []*Entry) []string {
+		var keys []string
+		for _, entry := range entries {
+			keys = append(keys, entry.Key)
+		}
+		return keys
+	}
+
+	// Case 1: Case- and accent-insensitive matching
+	if result := keys(file.Search("MULLER")); !reflect.DeepEqual([]string{"a", "b"}, result) {
+		t.Errorf("Expected '%#v', but got '%#v'", []string{"a", "b"}, result)
+	}
+
+	// Case 2: All terms must match
+	if result := keys(file.Search("  daten   2020 ")); !reflect.DeepEqual([]string{"a", "c"}, result) {
+		t.Errorf("Expected '%#v', but got '%#v'", []string{"a", "c"}, result)
+	}
+	if result := keys(file.Search("Müller Datenbanken")); result != nil {
+		t.Errorf("Expected no entries, but got '%#v'", result)
+	}
+
+	// Case 3: An empty query matches all entries
+	if result := file.Search(""); len(result) != 3 {
+		t.Errorf("Expected 3 entries, but got '%#v'", result)
+	}
+}
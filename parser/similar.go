@@ -0,0 +1,109 @@
+// The similar.go source file includes functions to find entries that are likely duplicates
+//
+// FindSimilar: groups the entries of a BibTeXFile with similar titles
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// FindSimilar groups the entries whose titles are similar, e.g., the same paper added
+// twice under different keys. It returns the indices of the entries in Entries, one
+// group per cluster of likely duplicates (in the order of their first entry, with the
+// indices in ascending order). Entries without a title are not grouped.
+//
+// The titles are normalized first: LaTeX accents are decoded, other LaTeX commands (e.g.,
+// \emph), braces, and accents are removed, the titles are lowercased, and all chars except
+// letters and digits are treated as white space (so "The {BibTeX} Book." and "the bibtex
+// book" are equal).
+// Two titles are similar if their Levenshtein ratio, 1 - distance / length of the
+// longer title (in runes), is at least threshold, e.g., 0.9 allows one typo per ten chars.
+// Groups are transitive: if a is similar to b and b to c, a, b, and c form one group.
+func (f *BibTeXFile) FindSimilar(threshold float64) [][]int {
+	titles := make([][]rune, len(f.Entries))
+	for i, entry := range f.Entries {
+		titles[i] = []rune(normalizeTitle(entry.Fields["title"]))
+	}
+	// Union-find of the similar entries, the root of a group is its first entry
+	parents := make([]int, len(f.Entries))
+	for i := range parents {
+		parents[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parents[i] != i {
+			parents[i] = root(parents[i])
+		}
+		return parents[i]
+	}
+	for i := range titles {
+		for j := i + 1; j < len(titles); j++ {
+			if len(titles[i]) == 0 || len(titles[j]) == 0 || titleSimilarity(titles[i], titles[j]) < threshold {
+				continue
+			}
+			rootI, rootJ := root(i), root(j)
+			parents[max(rootI, rootJ)] = min(rootI, rootJ)
+		}
+	}
+	// Collect the groups with more than one entry, the root is the first entry of its group
+	members := make(map[int][]int)
+	for i := range parents {
+		members[root(i)] = append(members[root(i)], i)
+	}
+	var groups [][]int
+	for i := range parents {
+		if group := members[i]; len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// Regex to match LaTeX commands like \emph, which are removed by normalizeTitle
+var regexLaTeXCommand = regexp.MustCompile(`\\[a-zA-Z]+`)
+
+// normalizeTitle returns the title without LaTeX commands, braces, accents, and
+// punctuation, lowercased and with single spaces between the words.
+func normalizeTitle(title string) string {
+	folded := foldASCII(stripBraces(regexLaTeXCommand.ReplaceAllString(DecodeLaTeX(title), "")))
+	return strings.Join(strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// titleSimilarity returns the Levenshtein ratio of the titles, which is 1 for equal
+// titles and 0 for titles without any common chars (or if one title is empty).
+func titleSimilarity(a, b []rune) float64 {
+	longer := max(len(a), len(b))
+	if longer == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longer)
+}
+
+// levenshtein returns the number of insertions, deletions, and substitutions of runes
+// needed to change a into b.
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,53 @@
+// Unit-tests for similar.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindSimilar(t *testing.T) {
+	file, _ := ParseString(`@article{a, title = {Neural Networks for {BibTeX} Parsing}}
+@misc{b, title = {Statistics in Practice}}
+@article{c, title = {Nueral networks for BibTeX parsing.}}
+@misc{d, note = {No title}}
+@misc{e, title = {Statistik in der Praxis}}
+@inproceedings{f, title = {Neural Networks for \emph{BibTeX} Parsin}}
+@misc{g, title = {Statistics in {P}ractice}}`)
+
+	// Case 1: Typos, punctuation, braces, and LaTeX commands do not matter
+	expected := [][]int{{0, 2, 5}, {1, 6}}
+	if groups := file.FindSimilar(0.9); !reflect.DeepEqual(expected, groups) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, groups)
+	}
+
+	// Case 2: Only equal normalized titles with the threshold 1
+	expected = [][]int{{1, 6}}
+	if groups := file.FindSimilar(1); !reflect.DeepEqual(expected, groups) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, groups)
+	}
+
+	// Case 3: Entries without a title are never grouped
+	if groups := file.FindSimilar(0); len(groups) != 1 || len(groups[0]) != 6 {
+		t.Errorf("Expected one group of 6 entries, but got '%#v'", groups)
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	// Case 1: Levenshtein ratio of the normalized titles
+	cases := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"the bibtex book", "The {BibTeX} Book.", 1},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"Müller", "muller", 1},
+		{"abc", "xyz", 0},
+		{"", "", 0},
+	}
+	for _, c := range cases {
+		if similarity := titleSimilarity([]rune(normalizeTitle(c.a)), []rune(normalizeTitle(c.b))); similarity != c.expected {
+			t.Errorf("Expected '%#v' for '%s' and '%s', but got '%#v'", c.expected, c.a, c.b, similarity)
+		}
+	}
+}
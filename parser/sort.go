@@ -0,0 +1,127 @@
+// The sort.go source file includes functions to sort the entries of a BibTeXFile
+//
+// Sort: sorts the entries with a comparator
+// ByKey: compares entries by their key
+// ByYear: compares entries by their publication year
+// ByFirstAuthor: compares entries by the last name of their first author
+// SortKey: returns the sortkey field or a key computed from the names, year, and title
+// BySortKey: compares entries like the nyt sorting scheme of biblatex
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sort sorts the entries in place, so that less(a, b) reports whether a comes before b.
+// The sort is stable, so entries that compare equal keep their original order.
+// Combined with WriteTo, Sort can be used to write reproducible, sorted BibTeX files.
+func (f *BibTeXFile) Sort(less func(a, b *Entry) bool) {
+	sort.SliceStable(f.Entries, func(i, j int) bool {
+		return less(f.Entries[i], f.Entries[j])
+	})
+	f.keyIndex = nil
+}
+
+// ByKey compares two entries by their key (case-sensitive, like BibTeX keys).
+func ByKey(a, b *Entry) bool {
+	return a.Key < b.Key
+}
+
+// ByYear compares two entries by their publication year, see Year.
+// Entries without a year come last.
+func ByYear(a, b *Entry) bool {
+	yearA, okA := a.Year()
+	yearB, okB := b.Year()
+	if okA != okB {
+		return okA
+	}
+	return yearA < yearB
+}
+
+// ByFirstAuthor compares two entries by the last name of their first author (or editor,
+// if there is no author), ignoring the case and braces. Entries without names come last.
+func ByFirstAuthor(a, b *Entry) bool {
+	nameA := a.firstAuthorLastName()
+	nameB := b.firstAuthorLastName()
+	if (nameA == "") != (nameB == "") {
+		return nameA != ""
+	}
+	return nameA < nameB
+}
+
+// firstAuthorLastName returns the lowercased last name of the first author or editor of the entry.
+func (e *Entry) firstAuthorLastName() string {
+	for _, fieldName := range []string{"author", "editor"} {
+		names := SplitNames(e.Fields[fieldName])
+		if len(names) == 0 {
+			continue
+		}
+		_, _, last, _ := ParseName(names[0])
+		return strings.ToLower(stripBraces(last))
+	}
+	return ""
+}
+
+// SortKey returns the key biblatex sorts the entry by: the sortkey field if present,
+// otherwise a key computed from the names (sortname, or author, or editor), the year
+// (sortyear or the year of Year), and the title (sorttitle or title), in this order, like
+// the nyt sorting scheme of biblatex. The names are given by their last and first names,
+// entries without names start with their title instead. The key is lowercased without
+// LaTeX commands and braces, e.g., "doe jane 2024 a title".
+func (e *Entry) SortKey() string {
+	if sortKey := strings.TrimSpace(e.Fields["sortkey"]); sortKey != "" {
+		return sortKey
+	}
+	title := e.Fields["sorttitle"]
+	if title == "" {
+		title = e.Fields["title"]
+	}
+	var parts []string
+	for _, fieldName := range []string{"sortname", "author", "editor"} {
+		names := SplitNames(e.Fields[fieldName])
+		for _, name := range names {
+			first, _, last, _ := ParseName(name)
+			parts = append(parts, last, first)
+		}
+		if len(names) > 0 {
+			break
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, title)
+	}
+	if sortYear := e.Fields["sortyear"]; sortYear != "" {
+		parts = append(parts, sortYear)
+	} else if year, ok := e.Year(); ok {
+		parts = append(parts, fmt.Sprintf("%04d", year))
+	}
+	parts = append(parts, title)
+	return strings.Join(strings.Fields(strings.ToLower(stripBraces(DecodeLaTeX(strings.Join(parts, " "))))), " ")
+}
+
+// The presort value of entries without a presort field, as in biblatex
+const defaultPresort = "mm"
+
+// BySortKey compares two entries like the nyt sorting scheme of biblatex: by
+// their presort field first (which defaults to "mm"), then by their SortKey. The fields
+// can be used to move single entries, e.g., presort = {zz} moves an entry to the end.
+func BySortKey(a, b *Entry) bool {
+	presortA, presortB := a.presort(), b.presort()
+	if presortA != presortB {
+		return presortA < presortB
+	}
+	return a.SortKey() < b.SortKey()
+}
+
+// presort returns the presort field of the entry or its default.
+func (e *Entry) presort() string {
+	if presort := strings.TrimSpace(e.Fields["presort"]); presort != "" {
+		return presort
+	}
+	return defaultPresort
+}
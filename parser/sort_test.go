@@ -0,0 +1,92 @@
+// Unit-tests for sort.go
+package parser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// entryKeys returns the keys of all entries in order.
+func entryKeys(entries []*Entry) []string {
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+func TestSort(t *testing.T) {
+	input := `@misc{c, author = {Zander, Anna}, year = {2020}}
+@misc{a, author = {Müller, Max and Adam, Eva}, year = {2024}}
+@misc{d, title = {Anonym}}
+@misc{b, editor = {{Barnes and Noble}}, date = {2020-01-01}}
+`
+	// Case 1: Sort by key
+	bibtexFile1, _ := ParseNewBibTeXFile(strings.NewReader(input))
+	bibtexFile1.Sort(ByKey)
+	expected1 := []string{"a", "b", "c", "d"}
+	if result1 := entryKeys(bibtexFile1.Entries); !reflect.DeepEqual(expected1, result1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, result1)
+	}
+
+	// Case 2: Sort by year is stable and puts entries without a year last
+	bibtexFile2, _ := ParseNewBibTeXFile(strings.NewReader(input))
+	bibtexFile2.Sort(ByYear)
+	expected2 := []string{"c", "b", "a", "d"}
+	if result2 := entryKeys(bibtexFile2.Entries); !reflect.DeepEqual(expected2, result2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, result2)
+	}
+
+	// Case 3: Sort by first author
+	bibtexFile3, _ := ParseNewBibTeXFile(strings.NewReader(input))
+	bibtexFile3.Sort(ByFirstAuthor)
+	expected3 := []string{"b", "a", "c", "d"}
+	if result3 := entryKeys(bibtexFile3.Entries); !reflect.DeepEqual(expected3, result3) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, result3)
+	}
+
+	// Case 4: The lookup index is still valid after sorting
+	if entry, ok := bibtexFile3.LookupByKey("c"); !ok || entry != bibtexFile3.Entries[2] {
+		t.Errorf("Expected '%#v', but got '%#v'", bibtexFile3.Entries[2], entry)
+	}
+}
+
+func TestSortKey(t *testing.T) {
+	// Case 1: The sortkey field or a key computed from the names, year, and title
+	cases := []struct {
+		entry    string
+		expected string
+	}{
+		{`@misc{a, author = {Doe, Jane}, year = {2024}, title = {A {Title}}}`, "doe jane 2024 a title"},
+		{`@misc{a, author = {Doe, Jane}, year = {2024}, sortkey = {Zz Key}}`, "Zz Key"},
+		{`@misc{a, author = {J. M{\"u}ller and Eva Adam}, date = {2020-01-01}, title = {T}}`, "müller j. adam eva 2020 t"},
+		{`@misc{a, editor = {Roe, Ann}, sortname = {Ann Zed}, sortyear = {1999}, title = {The T}, sorttitle = {T}}`, "zed ann 1999 t"},
+		{`@misc{a, title = {Anonymous}, year = {2001}}`, "anonymous 2001 anonymous"},
+	}
+	for _, c := range cases {
+		entry, _ := ParseNewEntry(c.entry)
+		if sortKey := entry.SortKey(); sortKey != c.expected {
+			t.Errorf("Expected '%#v', but got '%#v'", c.expected, sortKey)
+		}
+	}
+
+	// Case 2: Sort by presort and sort key
+	bibtexFile, _ := ParseString(`@misc{last, author = {Adam, Eva}, presort = {zz}}
+@misc{b, author = {Zander, Anna}, year = {2020}}
+@misc{a2, author = {Adam, Eva}, year = {2024}}
+@misc{a1, author = {Adam, Eva}, year = {2019}}
+@misc{key, author = {Zander, Anna}, sortkey = {aaa}}`)
+	bibtexFile.Sort(BySortKey)
+	expected := []string{"key", "a1", "a2", "b", "last"}
+	if keys := entryKeys(bibtexFile.Entries); !reflect.DeepEqual(expected, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, keys)
+	}
+
+	// Case 3: The sorting fields are known by all profiles
+	entry, _ := ParseNewEntry(`@misc{a, title = {T}, author = {A}, howpublished = {H}, year = {2024}, sortkey = {x}, presort = {y}, sortname = {z}}`)
+	if errs := entry.ValidateWithOptions(context.Background(), ValidateOptions{Profile: BibTeXStandard}); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+}
@@ -0,0 +1,49 @@
+// The stats.go source file includes functions to summarize the entries of a BibTeXFile
+//
+// Stats: counts the entries per entry type, the entries with a DOI, and common problems
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"context"
+	"errors"
+)
+
+// FileStats summarizes the entries of a BibTeXFile, see Stats.
+type FileStats struct {
+	Entries               int            // The number of entries.
+	EntryTypes            map[string]int // The number of entries per (lowercased) entry type.
+	WithDOI               int            // The number of entries with a non-empty doi field.
+	MissingRequiredFields int            // The number of entries missing at least one required field.
+	DuplicateKeys         int            // The number of keys shared by more than one entry.
+}
+
+// Stats returns an overview of the entries of the file, e.g., to check the health of a
+// large bibliography before looking at single problems. The required fields are checked
+// with the given profile (the DefaultProfile if nil) like by ValidateWithOptions, so entries
+// with a crossref should be resolved first, see ResolveCrossrefs. Entries without a key do
+// not count as duplicates.
+func (f *BibTeXFile) Stats(profile *ValidationProfile) FileStats {
+	stats := FileStats{Entries: len(f.Entries), EntryTypes: make(map[string]int)}
+	for _, entry := range f.Entries {
+		stats.EntryTypes[entry.EntryType]++
+		if entry.hasAnyNonEmptyField([]string{"doi"}) {
+			stats.WithDOI++
+		}
+		for _, err := range entry.ValidateWithOptions(context.Background(), ValidateOptions{Profile: profile}) {
+			var missing *ErrMissingField
+			if errors.As(err, &missing) {
+				stats.MissingRequiredFields++
+				break
+			}
+		}
+	}
+	for key, indices := range f.DuplicateKeys() {
+		if key != EmptyKey && len(indices) > 1 {
+			stats.DuplicateKeys++
+		}
+	}
+	return stats
+}
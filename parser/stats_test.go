@@ -0,0 +1,44 @@
+// Unit-tests for stats.go
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	file, _ := ParseString(`@article{a, author = {Jane Doe}, title = {A}, journal = {J}, year = {2020}, doi = {10.1000/182}}
+@Article{a, author = {Jane Doe}, title = {A2}, year = {2020}, doi = {}}
+@book{b, title = {B}, publisher = {P}, year = {2020}}
+@misc{c, title = {C}}
+@misc{, title = {No key}}`)
+
+	// Case 1: Counts of the entries and their problems
+	expected := FileStats{
+		Entries:               5,
+		EntryTypes:            map[string]int{"article": 2, "book": 1, "misc": 2},
+		WithDOI:               1,
+		MissingRequiredFields: 2,
+		DuplicateKeys:         1,
+	}
+	if stats := file.Stats(nil); !reflect.DeepEqual(expected, stats) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, stats)
+	}
+
+	// Case 2: Empty files
+	empty, _ := ParseString("")
+	expected = FileStats{EntryTypes: map[string]int{}}
+	if stats := empty.Stats(nil); !reflect.DeepEqual(expected, stats) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, stats)
+	}
+
+	// Case 3: The required fields of the given profile are checked
+	profile, err := LoadValidationProfile(strings.NewReader(`{"extends": "default", "entryTypes": {"misc": [{"fields": ["author"]}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := file.Stats(profile); stats.MissingRequiredFields != 4 {
+		t.Errorf("Expected '%#v', but got '%#v'", 4, stats.MissingRequiredFields)
+	}
+}
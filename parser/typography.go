@@ -0,0 +1,61 @@
+// The typography.go source file includes a check for typographic chars pasted into field values
+//
+// checkTypography: reports smart quotes, Unicode dashes, and special spaces in a field value
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import "fmt"
+
+// Define errors
+type ErrTypographicChar struct {
+	Key        string
+	Field      string
+	Char       rune
+	Position   int    // The index of the first occurrence of the char in the value (in runes).
+	Suggestion string // The ASCII or LaTeX equivalent of the char.
+}
+
+func (e *ErrTypographicChar) Error() string {
+	return fmt.Sprintf("Warning validating BibTeX entry '%s': field '%s' contains %q (U+%04X) at position %d, use %s instead", e.Key, e.Field, e.Char, e.Char, e.Position, e.Suggestion)
+}
+
+// typographicChars maps chars that are typically copy-pasted from word processors or
+// websites and break (or confuse) LaTeX without Unicode support to their ASCII or
+// LaTeX equivalents.
+var typographicChars = map[rune]string{
+	'“':      "``",     // Left double quotation mark
+	'”':      "''",     // Right double quotation mark
+	'„':      `\glqq`,  // Double low-9 quotation mark (German opening quote)
+	'‘':      "`",      // Left single quotation mark
+	'’':      "'",      // Right single quotation mark (also used as apostrophe)
+	'‚':      `\glq`,   // Single low-9 quotation mark
+	'–':      "--",     // En dash
+	'—':      "---",    // Em dash
+	'−':      "$-$",    // Minus sign
+	'…':      `\ldots`, // Horizontal ellipsis
+	'\u00a0': "~",      // Non-breaking space
+	'\u202f': `\,`,     // Narrow non-breaking space
+	'\u2009': `\,`,     // Thin space
+	'\u00ad': `\-`,     // Soft hyphen
+}
+
+// checkTypography reports every typographic char (see typographicChars) in the value of
+// the field once, with the position of its first occurrence, as an ErrTypographicChar warning.
+func checkTypography(key, field, value string) []error {
+	var errs []error
+	var reported map[rune]bool
+	position := 0
+	for _, char := range value {
+		if suggestion, ok := typographicChars[char]; ok && !reported[char] {
+			errs = append(errs, &ErrTypographicChar{Key: key, Field: field, Char: char, Position: position, Suggestion: suggestion})
+			if reported == nil {
+				reported = make(map[rune]bool)
+			}
+			reported[char] = true
+		}
+		position++
+	}
+	return errs
+}
@@ -0,0 +1,32 @@
+// Unit-tests for typography.go
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckTypography(t *testing.T) {
+	// Case 1: Every char is reported once, at its first position (in runes)
+	expected := []error{
+		&ErrTypographicChar{Key: "id", Field: "title", Char: '“', Position: 4, Suggestion: "``"},
+		&ErrTypographicChar{Key: "id", Field: "title", Char: '”', Position: 10, Suggestion: "''"},
+		&ErrTypographicChar{Key: "id", Field: "title", Char: '–', Position: 12, Suggestion: "--"},
+		&ErrTypographicChar{Key: "id", Field: "title", Char: '\u00a0', Position: 17, Suggestion: "~"},
+	}
+	if errs := checkTypography("id", "title", "Die “Größe” – ein\u00a0“Test”"); !reflect.DeepEqual(expected, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, errs)
+	}
+
+	// Case 2: ASCII and LaTeX quotes and dashes are fine
+	if errs := checkTypography("id", "title", "``Quoted'' text -- with -- dashes and Größe"); errs != nil {
+		t.Errorf("Expected no warnings, but got '%#v'", errs)
+	}
+
+	// Case 3: The warnings are reported by Validate
+	entry, _ := ParseNewEntry(`@misc{m, title = {O’Connor’s Notes}}`)
+	errs := entry.Validate()
+	if len(errs) != 1 || !IsWarning(errs[0]) || errs[0].Error() != `Warning validating BibTeX entry 'm': field 'title' contains '’' (U+2019) at position 1, use ' instead` {
+		t.Errorf("Expected an ErrTypographicChar warning, but got '%#v'", errs)
+	}
+}
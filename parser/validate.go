@@ -0,0 +1,409 @@
+// The validate.go source file includes functions to verify parsed BibTeX entries
+//
+// Validate: checks an Entry for the required fields of its entry type and malformed field values
+// IsWarning: reports whether an error is only a warning
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Define errors
+type ErrMissingField struct {
+	EntryType string
+	Field     string
+	Key       string
+}
+
+type ErrUnknownEntryType struct {
+	EntryType string
+	Key       string
+}
+
+type ErrEmptyRequiredField struct {
+	EntryType string
+	Field     string
+	Key       string
+}
+
+type ErrExclusiveFields struct {
+	EntryType string
+	Fields    []string // The fields of which only one is allowed.
+	Key       string
+}
+
+type ErrUnknownField struct {
+	Key   string
+	Field string
+}
+
+type ErrDiscouragedField struct {
+	EntryType string
+	Field     string
+	Key       string
+}
+
+type ErrDuplicateField struct {
+	Key   string
+	Field string
+}
+
+type ErrMissingKey struct {
+	EntryType string
+	Context   string // A field of the entry to locate it, e.g., title = {A Title}.
+	Line      int    // The line of the entry's '@' in the BibTeX file (0 if unknown).
+	Offset    int    // The byte offset of the entry's '@' in the parsed input, see Entry.StartOffset.
+}
+
+func (e *ErrMissingField) Error() string {
+	if strings.Contains(e.Field, "/") {
+		return fmt.Sprintf("Error validating BibTeX entry '%s': %s requires %s", e.Key, e.EntryType, quoteFieldNames(strings.Split(e.Field, "/"), "or"))
+	}
+	return fmt.Sprintf("Error validating BibTeX entry '%s': %s is missing required field '%s'", e.Key, e.EntryType, e.Field)
+}
+
+func (e *ErrEmptyRequiredField) Error() string {
+	return fmt.Sprintf("Error validating BibTeX entry '%s': required field '%s' of %s is empty", e.Key, e.Field, e.EntryType)
+}
+
+func (e *ErrExclusiveFields) Error() string {
+	return fmt.Sprintf("Warning validating BibTeX entry '%s': %s cannot have both %s", e.Key, e.EntryType, quoteFieldNames(e.Fields, "and"))
+}
+
+func (e *ErrUnknownEntryType) Error() string {
+	return fmt.Sprintf("Error validating BibTeX entry '%s': unknown entry type '%s'", e.Key, e.EntryType)
+}
+
+func (e *ErrUnknownField) Error() string {
+	return fmt.Sprintf("Warning validating BibTeX entry '%s': unknown field '%s'", e.Key, e.Field)
+}
+
+func (e *ErrDiscouragedField) Error() string {
+	return fmt.Sprintf("Warning validating BibTeX entry '%s': field '%s' is unusual for %s, maybe the entry type was changed", e.Key, e.Field, e.EntryType)
+}
+
+func (e *ErrMissingKey) Error() string {
+	prefix := "Error validating BibTeX entry"
+	if e.Line > 0 {
+		prefix = fmt.Sprintf("Error validating BibTeX entry (line %d)", e.Line)
+	}
+	if e.Context == "" {
+		return fmt.Sprintf("%s: %s has no key", prefix, e.EntryType)
+	}
+	return fmt.Sprintf("%s: %s has no key (%s)", prefix, e.EntryType, e.Context)
+}
+
+func (e *ErrDuplicateField) Error() string {
+	return fmt.Sprintf("Warning validating BibTeX entry '%s': field '%s' appears more than once, only the last value is used", e.Key, e.Field)
+}
+
+// warning is implemented by all errors that are only warnings, see IsWarning.
+type warning interface {
+	isWarning()
+}
+
+func (e *ErrUnknownField) isWarning()         {}
+func (e *ErrDuplicateField) isWarning()       {}
+func (e *ErrMissingComma) isWarning()         {}
+func (e *ErrTypographicChar) isWarning()      {}
+func (e *ErrExtraComma) isWarning()           {}
+func (e *ErrExclusiveFields) isWarning()      {}
+func (e *ErrUnmappedField) isWarning()        {}
+func (e *ErrPagesHyphen) isWarning()          {}
+func (e *ErrSuspiciousValue) isWarning()      {}
+func (e *ErrUnknownMonth) isWarning()         {}
+func (e *ErrUnusedEntry) isWarning()          {}
+func (e *ErrInconsistentNameForm) isWarning() {}
+func (e *ErrStrayText) isWarning()            {}
+func (e *ErrDiscouragedField) isWarning()     {}
+func (e *ErrMissingPrimaryClass) isWarning()  {}
+func (e *ErrKeyCollision) isWarning()         {}
+func (e *ErrMissingClosingBrace) isWarning()  {}
+
+// IsWarning reports whether err (or any error it wraps) is only a warning, e.g., an
+// ErrUnknownField. Warnings point to possible problems, but the entry is still usable.
+func IsWarning(err error) bool {
+	var w warning
+	return errors.As(err, &w)
+}
+
+// FieldRule is a rule for the fields of an entry type, see ValidationProfile.
+type FieldRule struct {
+	Fields    []string // The alternative fields of the rule, e.g., author or editor.
+	Optional  bool     // None of the fields is required (for rules that only restrict combinations).
+	Exclusive bool     // At most one of the fields may be present, e.g., not both volume and number.
+	Unless    string   // The rule does not apply if this field is present, e.g., crossref.
+}
+
+// requires returns a rule requiring at least one of the given alternative fields.
+func requires(fields ...string) FieldRule {
+	return FieldRule{Fields: fields}
+}
+
+// atMostOne returns a rule forbidding more than one of the given fields, none of which is required.
+func atMostOne(fields ...string) FieldRule {
+	return FieldRule{Fields: fields, Optional: true, Exclusive: true}
+}
+
+// unless returns a copy of the rule, which does not apply if the given field is present.
+func (r FieldRule) unless(field string) FieldRule {
+	r.Unless = field
+	return r
+}
+
+// requiredFieldNames returns the alternative fields of the rule separated by '/', e.g., author/editor.
+func (r FieldRule) requiredFieldNames() string {
+	return strings.Join(r.Fields, "/")
+}
+
+// fieldRules maps the standard BibTeX entry types to the rules for their fields, following
+// the standard BibTeX styles: alternatives (e.g., a book requires an author or an editor),
+// combinations that are not allowed (e.g., author and editor, or volume and number), and rules
+// that do not apply if the entry has a crossref (and inherits fields, see ResolveCrossrefs).
+var fieldRules = map[EntryType][]FieldRule{
+	EntryArticle:       {requires("author"), requires("title"), requires("journal").unless("crossref"), requires("year")},
+	EntryBook:          {requires("author", "editor"), atMostOne("author", "editor").unless("crossref"), requires("title"), requires("publisher").unless("crossref"), requires("year"), atMostOne("volume", "number")},
+	EntryBooklet:       {requires("title")},
+	EntryConference:    {requires("author"), requires("title"), requires("booktitle").unless("crossref"), requires("year"), atMostOne("volume", "number")},
+	EntryInBook:        {requires("author", "editor"), atMostOne("author", "editor").unless("crossref"), requires("title"), requires("chapter", "pages"), requires("publisher").unless("crossref"), requires("year"), atMostOne("volume", "number")},
+	EntryInCollection:  {requires("author"), requires("title"), requires("booktitle").unless("crossref"), requires("publisher").unless("crossref"), requires("year"), atMostOne("volume", "number")},
+	EntryInProceedings: {requires("author"), requires("title"), requires("booktitle").unless("crossref"), requires("year"), atMostOne("volume", "number")},
+	EntryManual:        {requires("title")},
+	EntryMastersThesis: {requires("author"), requires("title"), requires("school"), requires("year")},
+	EntryMisc:          {},
+	EntryPhDThesis:     {requires("author"), requires("title"), requires("school"), requires("year")},
+	EntryProceedings:   {requires("title"), requires("year"), atMostOne("volume", "number")},
+	EntryTechReport:    {requires("author"), requires("title"), requires("institution"), requires("year")},
+	EntryUnpublished:   {requires("author"), requires("title"), requires("note")},
+}
+
+// discouragedFields maps the standard BibTeX entry types to the fields that belong to other
+// entry types, e.g., journal to article or school to the theses, and are often left behind
+// when the entry type of an entry is changed.
+var discouragedFields = map[EntryType][]string{
+	EntryArticle:       {"booktitle", "chapter", "edition", "institution", "publisher", "school"},
+	EntryBook:          {"booktitle", "institution", "journal", "school"},
+	EntryConference:    {"journal", "school"},
+	EntryInBook:        {"institution", "journal", "school"},
+	EntryInCollection:  {"institution", "journal", "school"},
+	EntryInProceedings: {"journal", "school"},
+	EntryManual:        {"journal", "school"},
+	EntryMastersThesis: {"booktitle", "journal"},
+	EntryPhDThesis:     {"booktitle", "journal"},
+	EntryProceedings:   {"journal", "school"},
+	EntryTechReport:    {"booktitle", "journal"},
+}
+
+// ValidateOptions control the optional checks of ValidateWithOptions.
+type ValidateOptions struct {
+	ResolveDOIs   bool               // Check that DOIs resolve via doi.org (requires network access), see ResolveDOI.
+	AllowedFields []string           // Additional field names that are not reported as ErrUnknownField (e.g., custom fields).
+	Profile       *ValidationProfile // The known entry types and fields (DefaultProfile if nil), e.g., Biblatex.
+}
+
+// Validate checks if the entry follows the field rules of its entry type in the DefaultProfile.
+// It returns one ErrMissingField per missing required field (or missing alternatives like
+// author or editor) and one ErrEmptyRequiredField per required field that is present, but
+// blank (e.g., title = {}). Fields that must not be combined (e.g., volume and number) are
+// reported as ErrExclusiveFields warnings, and fields that belong to other entry types (e.g.,
+// journal on a book, see ValidationProfile.DiscouragedFields) as ErrDiscouragedField
+// warnings. If the entry type is not known, a single ErrUnknownEntryType is returned instead
+// of the missing fields.
+// Additionally, the values of fields with a known format (e.g., doi) and arXiv eprints (see
+// ArxivURL) are checked, smart quotes, Unicode dashes, and special spaces are reported as
+// ErrTypographicChar warnings, and every field that is not a known field of the profile is
+// reported as an ErrUnknownField warning (see IsWarning), which helps to find typos like
+// titel.
+// Fields that appear more than once in the entry are reported as ErrDuplicateField warnings,
+// followed by the ParseWarnings of the entry (e.g., a missing ',' between two fields).
+// An entry without a key (e.g., @misc{, title = {A}}) is reported as an ErrMissingKey
+// with one of its fields and its line to locate it. Keys with invalid chars (e.g., "my key")
+// are only reported as an ErrInvalidKey while parsing.
+// An empty slice means that the entry is valid.
+// Validate only runs offline checks, see ValidateWithOptions.
+func (e *Entry) Validate() []error {
+	return e.ValidateWithOptions(context.Background(), ValidateOptions{})
+}
+
+// contextFields are the fields used to locate an entry without a key, in order of preference.
+var contextFields = []string{"title", "author", "editor", "year"}
+
+// keyContext returns the first non-empty field of contextFields (or of all fields in
+// alphabetical order) as name = {value}, shortened to 40 chars. It returns an empty
+// string if the entry has no non-empty fields.
+func (e *Entry) keyContext() string {
+	fieldNames := append([]string{}, contextFields...)
+	otherFields := make([]string, 0, len(e.Fields))
+	for fieldName := range e.Fields {
+		otherFields = append(otherFields, fieldName)
+	}
+	sort.Strings(otherFields)
+	for _, fieldName := range append(fieldNames, otherFields...) {
+		value := strings.TrimSpace(e.Fields[fieldName])
+		if len(value) == 0 {
+			continue
+		}
+		return fmt.Sprintf("%s = {%s}", fieldName, truncateRunes(value, 40))
+	}
+	return ""
+}
+
+// hasInvalidKey reports whether the raw entry has a key with invalid chars (e.g., "my key"),
+// which is not parsed into the Key of the entry, but reported as an ErrInvalidKey.
+func (e *Entry) hasInvalidKey() bool {
+	var invalidKey *ErrInvalidKey
+	_, err := parseID(e.CleanEntry)
+	return errors.As(err, &invalidKey)
+}
+
+// truncateRunes shortens s to its first n runes followed by "..." if it is longer.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
+
+// ValidateWithOptions validates the entry like Validate, using the ValidationProfile of
+// opts, and runs the optional checks enabled in opts. The context is used for checks
+// that require network access.
+func (e *Entry) ValidateWithOptions(ctx context.Context, opts ValidateOptions) []error {
+	var errs []error
+	if e.Key == "" && !e.hasInvalidKey() {
+		errs = append(errs, &ErrMissingKey{EntryType: e.EntryType, Context: e.keyContext(), Line: e.Line, Offset: e.StartOffset})
+	}
+	profile := opts.Profile
+	if profile == nil {
+		profile = DefaultProfile
+	}
+	entryType := strings.ToLower(e.EntryType)
+	rules, ok := profile.EntryTypes[EntryType(entryType)]
+	if !ok {
+		errs = append(errs, &ErrUnknownEntryType{EntryType: e.EntryType, Key: e.Key})
+	}
+	for _, rule := range rules {
+		if _, ok := e.Fields[rule.Unless]; ok && rule.Unless != "" {
+			continue
+		}
+		switch {
+		case rule.Optional:
+		case !e.hasAnyField(rule.Fields):
+			errs = append(errs, &ErrMissingField{EntryType: entryType, Field: rule.requiredFieldNames(), Key: e.Key})
+		case !e.hasAnyNonEmptyField(rule.Fields):
+			errs = append(errs, &ErrEmptyRequiredField{EntryType: entryType, Field: rule.requiredFieldNames(), Key: e.Key})
+		}
+		if present := e.presentFields(rule.Fields); rule.Exclusive && len(present) > 1 {
+			errs = append(errs, &ErrExclusiveFields{EntryType: entryType, Fields: present, Key: e.Key})
+		}
+	}
+	// Check field values in a stable order
+	fieldNames := make([]string, 0, len(e.Fields))
+	for fieldName := range e.Fields {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
+		if validator, ok := fieldValidators[fieldName]; ok {
+			if err := validator(e.Key, e.Fields[fieldName]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		errs = append(errs, checkTypography(e.Key, fieldName, e.Fields[fieldName])...)
+	}
+	errs = append(errs, e.checkArxiv()...)
+	// Report unknown field names
+	for _, fieldName := range fieldNames {
+		if profile.KnownFields != nil && !profile.KnownFields[fieldName] && !containsFold(opts.AllowedFields, fieldName) {
+			errs = append(errs, &ErrUnknownField{Key: e.Key, Field: fieldName})
+		}
+	}
+	// Report fields of other entry types, e.g., left behind after changing the entry type
+	for _, fieldName := range profile.DiscouragedFields[EntryType(entryType)] {
+		if _, present := e.Fields[fieldName]; present && ok {
+			errs = append(errs, &ErrDiscouragedField{EntryType: entryType, Field: fieldName, Key: e.Key})
+		}
+	}
+	// Report repeated fields once per field name
+	reported := make(map[string]bool, len(e.DuplicateFields))
+	for _, fieldName := range e.DuplicateFields {
+		if !reported[fieldName] {
+			errs = append(errs, &ErrDuplicateField{Key: e.Key, Field: fieldName})
+			reported[fieldName] = true
+		}
+	}
+	// Syntax problems that were tolerated by the parser
+	errs = append(errs, e.ParseWarnings...)
+	// Optional network checks
+	if doi, ok := e.Fields["doi"]; ok && opts.ResolveDOIs && regexDOI.MatchString(doi) {
+		resolved, err := ResolveDOI(ctx, doi)
+		if err != nil {
+			errs = append(errs, err)
+		} else if !resolved {
+			errs = append(errs, &ErrUnresolvedDOI{Key: e.Key, Value: doi})
+		}
+	}
+	return errs
+}
+
+// hasAnyField checks if at least one of the given field names exists in the entry.
+func (e *Entry) hasAnyField(fieldNames []string) bool {
+	for _, fieldName := range fieldNames {
+		if _, ok := e.Fields[fieldName]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// presentFields returns the given field names that exist in the entry.
+func (e *Entry) presentFields(fieldNames []string) []string {
+	var present []string
+	for _, fieldName := range fieldNames {
+		if _, ok := e.Fields[fieldName]; ok {
+			present = append(present, fieldName)
+		}
+	}
+	return present
+}
+
+// hasAnyNonEmptyField checks if at least one of the given field names exists in the entry
+// and has a value that is not blank.
+func (e *Entry) hasAnyNonEmptyField(fieldNames []string) bool {
+	for _, fieldName := range fieldNames {
+		if strings.TrimSpace(e.Fields[fieldName]) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteFieldNames joins the quoted field names with commas and the conjunction before
+// the last name, e.g., 'author' or 'editor'.
+func quoteFieldNames(fieldNames []string, conjunction string) string {
+	quoted := make([]string, len(fieldNames))
+	for i, fieldName := range fieldNames {
+		quoted[i] = "'" + fieldName + "'"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " " + conjunction + " " + quoted[len(quoted)-1]
+}
+
+// containsFold checks if the list contains the value, ignoring the case.
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
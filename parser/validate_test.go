@@ -0,0 +1,272 @@
+// Unit-tests for validate.go
+package parser
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	// Case 1: Valid article
+	entry1, _ := ParseNewEntry(`@article{muster2024,
+  author  = {Max Mustermann},
+  title   = {Einführung in die Datenwissenschaft},
+  journal = {Journal für Informatik},
+  year    = {2024}
+}`)
+	errs1 := entry1.Validate()
+	if len(errs1) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs1)
+	}
+
+	// Case 2: Article missing journal and year
+	entry2, _ := ParseNewEntry(`@article{muster2024,
+  author  = {Max Mustermann},
+  title   = {Einführung in die Datenwissenschaft}
+}`)
+	expected2 := []error{
+		&ErrMissingField{EntryType: "article", Field: "journal", Key: "muster2024"},
+		&ErrMissingField{EntryType: "article", Field: "year", Key: "muster2024"},
+	}
+	errs2 := entry2.Validate()
+	if !reflect.DeepEqual(expected2, errs2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, errs2)
+	}
+
+	// Case 3: Book with editor instead of author
+	entry3, _ := ParseNewEntry(`@book{weber2020,
+  editor    = {Weber, Eva},
+  title     = {Sammelband},
+  publisher = {Technik Verlag},
+  year      = {2020}
+}`)
+	errs3 := entry3.Validate()
+	if len(errs3) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs3)
+	}
+
+	// Case 4: Book without author and editor
+	entry4, _ := ParseNewEntry(`@book{weber2020,
+  title     = {Sammelband},
+  publisher = {Technik Verlag},
+  year      = {2020}
+}`)
+	expected4 := []error{&ErrMissingField{EntryType: "book", Field: "author/editor", Key: "weber2020"}}
+	errs4 := entry4.Validate()
+	if !reflect.DeepEqual(expected4, errs4) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected4, errs4)
+	}
+
+	// Case 5: Unknown entry type
+	entry5, _ := ParseNewEntry(`@blogpost{muster2024, title = {Ein Blog}}`)
+	expected5 := []error{&ErrUnknownEntryType{EntryType: "blogpost", Key: "muster2024"}}
+	errs5 := entry5.Validate()
+	if !reflect.DeepEqual(expected5, errs5) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected5, errs5)
+	}
+
+	// Case 6: Malformed DOI
+	entry6, _ := ParseNewEntry(`@misc{muster2024, doi = {doi:10.1000/182}}`)
+	expected6 := []error{&ErrInvalidDOI{Key: "muster2024", Value: "doi:10.1000/182"}}
+	errs6 := entry6.Validate()
+	if !reflect.DeepEqual(expected6, errs6) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected6, errs6)
+	}
+
+	// Case 7: Present, but empty required fields
+	entry8, _ := ParseNewEntry(`@book{weber2020,
+  editor    = {  },
+  title     = {},
+  publisher = "",
+  year      = {2020}
+}`)
+	expected8 := []error{
+		&ErrEmptyRequiredField{EntryType: "book", Field: "author/editor", Key: "weber2020"},
+		&ErrEmptyRequiredField{EntryType: "book", Field: "title", Key: "weber2020"},
+		&ErrEmptyRequiredField{EntryType: "book", Field: "publisher", Key: "weber2020"},
+	}
+	errs8 := entry8.Validate()
+	if !reflect.DeepEqual(expected8, errs8) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected8, errs8)
+	}
+
+	// Case 8: Entry type is compared case-insensitively
+	entry7, _ := ParseNewEntry(`@MISC{muster2024, title = {Irgendwas}}`)
+	errs7 := entry7.Validate()
+	if len(errs7) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs7)
+	}
+
+	// Case 9: Unknown field names are reported as warnings
+	entry9, _ := ParseNewEntry(`@misc{muster2024, titel = {Irgendwas}, athor = {Max Mustermann}, abstract = {Kurz}}`)
+	expected9 := []error{
+		&ErrUnknownField{Key: "muster2024", Field: "athor"},
+		&ErrUnknownField{Key: "muster2024", Field: "titel"},
+	}
+	errs9 := entry9.Validate()
+	if !reflect.DeepEqual(expected9, errs9) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected9, errs9)
+	}
+
+	// Case 10: Additional allowed fields
+	entry10, _ := ParseNewEntry(`@misc{muster2024, title = {Irgendwas}, owner = {thomas}}`)
+	errs10 := entry10.ValidateWithOptions(context.Background(), ValidateOptions{AllowedFields: []string{"Owner"}})
+	if len(errs10) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs10)
+	}
+}
+
+func TestValidateFieldRules(t *testing.T) {
+	// Case 1: Neither of the alternatives is present
+	entry1, _ := ParseNewEntry(`@book{weber2020, title = {A}, publisher = {B}, year = {2020}}`)
+	errs1 := entry1.Validate()
+	expected1 := "Error validating BibTeX entry 'weber2020': book requires 'author' or 'editor'"
+	if len(errs1) != 1 || expected1 != errs1[0].Error() {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, errs1)
+	}
+
+	// Case 2: Fields that must not be combined
+	entry2, _ := ParseNewEntry(`@book{weber2020, author = {A}, editor = {E}, title = {A}, publisher = {B}, year = {2020}, volume = {1}, number = {2}}`)
+	expected2 := []error{
+		&ErrExclusiveFields{EntryType: "book", Fields: []string{"author", "editor"}, Key: "weber2020"},
+		&ErrExclusiveFields{EntryType: "book", Fields: []string{"volume", "number"}, Key: "weber2020"},
+	}
+	errs2 := entry2.Validate()
+	if !reflect.DeepEqual(expected2, errs2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, errs2)
+	}
+	if len(errs2) > 0 && !IsWarning(errs2[0]) {
+		t.Errorf("Expected '%#v' to be a warning", errs2[0])
+	}
+
+	// Case 3: Fields inherited via crossref are not required
+	entry3, _ := ParseNewEntry(`@inproceedings{doe2020, author = {A}, title = {T}, year = {2020}, crossref = {conf2020}}`)
+	if errs3 := entry3.Validate(); len(errs3) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs3)
+	}
+}
+
+func TestValidateDiscouragedFields(t *testing.T) {
+	// Case 1: Fields of other entry types, e.g., after changing an article into a book
+	entry1, _ := ParseNewEntry(`@book{weber2020, author = {A}, title = {T}, journal = {J}, publisher = {P}, year = {2020}}`)
+	expected1 := []error{&ErrDiscouragedField{EntryType: "book", Field: "journal", Key: "weber2020"}}
+	errs1 := entry1.Validate()
+	if !reflect.DeepEqual(expected1, errs1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, errs1)
+	}
+	if len(errs1) > 0 && !IsWarning(errs1[0]) {
+		t.Errorf("Expected '%#v' to be a warning", errs1[0])
+	}
+
+	// Case 2: The discouraged fields depend on the profile
+	entry2, _ := ParseNewEntry(`@thesis{doe2021, author = {A}, title = {T}, type = {phdthesis}, institution = {I}, date = {2021}, journaltitle = {J}}`)
+	expected2 := []error{&ErrDiscouragedField{EntryType: "thesis", Field: "journaltitle", Key: "doe2021"}}
+	if errs2 := entry2.ValidateWithOptions(context.Background(), ValidateOptions{Profile: Biblatex}); !reflect.DeepEqual(expected2, errs2) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, errs2)
+	}
+	entry3, _ := ParseNewEntry(`@article{doe2022, author = {A}, title = {T}, journal = {J}, year = {2022}, publisher = {P}}`)
+	expected3 := "Warning validating BibTeX entry 'doe2022': field 'publisher' is unusual for article, maybe the entry type was changed"
+	if errs3 := entry3.ValidateWithOptions(context.Background(), ValidateOptions{Profile: BibTeXStandard}); len(errs3) != 1 || errs3[0].Error() != expected3 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, errs3)
+	}
+
+	// Case 3: Entries of unknown entry types and misc entries
+	entry4, _ := ParseNewEntry(`@misc{doe2023, title = {T}, journal = {J}, publisher = {P}, school = {S}}`)
+	if errs4 := entry4.Validate(); len(errs4) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs4)
+	}
+}
+
+func TestValidateDuplicateFields(t *testing.T) {
+	// Case 1: Each repeated field is reported once
+	entry1, _ := ParseNewEntry(`@misc{doe2023, year = {2023}, note = {A}, year = {2024}, note = {B}, year = {2025}}`)
+	expected1 := []error{
+		&ErrDuplicateField{Key: "doe2023", Field: "year"},
+		&ErrDuplicateField{Key: "doe2023", Field: "note"},
+	}
+	errs1 := entry1.Validate()
+	if !reflect.DeepEqual(expected1, errs1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, errs1)
+	}
+	if !IsWarning(errs1[0]) {
+		t.Errorf("Expected '%#v' to be a warning", errs1[0])
+	}
+}
+
+func TestValidateMissingKey(t *testing.T) {
+	// Case 1: An entry without a key is an error with the title as context
+	entry1, _ := ParseNewEntry(`@misc{,title={A Very Long Title of a Misc Entry Without Any Key}, year = {2024}}`)
+	expected1 := []error{&ErrMissingKey{EntryType: "misc", Context: "title = {A Very Long Title of a Misc Entry Withou...}"}}
+	errs1 := entry1.Validate()
+	if !reflect.DeepEqual(expected1, errs1) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected1, errs1)
+	}
+	if IsWarning(errs1[0]) {
+		t.Errorf("Expected '%#v' not to be a warning", errs1[0])
+	}
+
+	// Case 2: Other fields are used if there is no title, author, editor, or year
+	entry2 := &Entry{EntryType: "misc", Fields: map[string]string{"title": " ", "url": "https://example.com", "note": "N"}}
+	expected2 := "Error validating BibTeX entry: misc has no key (note = {N})"
+	if errs2 := entry2.Validate(); len(errs2) == 0 || errs2[0].Error() != expected2 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected2, errs2)
+	}
+
+	// Case 3: Entries without fields
+	expected3 := "Error validating BibTeX entry: misc has no key"
+	if err := (&ErrMissingKey{EntryType: "misc"}).Error(); err != expected3 {
+		t.Errorf("Expected '%#v', but got '%#v'", expected3, err)
+	}
+
+	// Case 4: Keys with invalid chars are only reported as ErrInvalidKey while parsing
+	entry4, err4 := ParseNewEntry(`@misc{my key, title = {A}}`)
+	expected4 := &ErrInvalidKey{Key: "my key", BadChar: ' '}
+	var invalidKey4 *ErrInvalidKey
+	if !errors.As(err4, &invalidKey4) || !reflect.DeepEqual(expected4, invalidKey4) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected4, err4)
+	}
+	if errs4 := entry4.Validate(); len(errs4) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs4)
+	}
+
+	// Case 5: A missing key is reported once in a file, with the position of the entry
+	file5, _ := ParseNewBibTeXFile(strings.NewReader("@misc{a, title = {A}}\n\n@misc{,title={x}}\n"))
+	if len(file5.Errors) != 0 {
+		t.Errorf("Expected no parsing errors, but got '%#v'", file5.Errors)
+	}
+	expected5 := []error{&ErrMissingKey{EntryType: "misc", Context: "title = {x}", Line: 3, Offset: 23}}
+	errs5 := file5.Entries[1].Validate()
+	if !reflect.DeepEqual(expected5, errs5) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected5, errs5)
+	}
+	expectedPosition5 := Position{Line: 3, Offset: 23}
+	if diagnostics5 := file5.DiagnosticsOf(errs5); len(diagnostics5) != 1 || diagnostics5[0].Position != expectedPosition5 {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedPosition5, diagnostics5)
+	}
+	expectedMessage5 := "Error validating BibTeX entry (line 3): misc has no key (title = {x})"
+	if errs5[0].Error() != expectedMessage5 {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedMessage5, errs5[0].Error())
+	}
+}
+
+func TestIsWarning(t *testing.T) {
+	// Case 1: Warnings, also if wrapped
+	for _, err := range []error{
+		&ErrUnknownField{Key: "id", Field: "titel"},
+		&ErrUnmappedField{Key: "id", Field: "owner", Format: "RIS"},
+		errors.Join(&ErrUnknownField{Key: "id", Field: "titel"}),
+	} {
+		if !IsWarning(err) {
+			t.Errorf("Expected '%#v' to be a warning", err)
+		}
+	}
+	// Case 2: Errors
+	for _, err := range []error{nil, &ErrMissingField{EntryType: "article", Field: "title", Key: "id"}} {
+		if IsWarning(err) {
+			t.Errorf("Expected '%#v' not to be a warning", err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// The xdata.go source file includes functions for biblatex @xdata entries
+//
+// XData: returns the keys of the @xdata entries referenced by the xdata field of an Entry
+// ResolveXData: copies the fields of the referenced @xdata entries into the entries
+//
+// Author: Thomas Jurczyk
+// Date: October 14, 2026
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Define errors
+type ErrMissingXData struct {
+	Key   string
+	XData string
+}
+
+func (e *ErrMissingXData) Error() string {
+	return fmt.Sprintf("Error resolving BibTeX entry '%s': xdata entry '%s' does not exist", e.Key, e.XData)
+}
+
+// nonXDataFields are the fields of @xdata entries that are not copied by ResolveXData.
+var nonXDataFields = map[string]bool{"ids": true, "xdata": true}
+
+// XData returns the keys of the xdata field (e.g., xdata = {foo, bar}), which references
+// biblatex @xdata entries providing reusable fields, e.g., the publisher of a series.
+// The keys are trimmed and empty keys are dropped. It returns nil if the entry has no
+// xdata field.
+func (e *Entry) XData() []string {
+	return splitKeyList(e.Fields["xdata"])
+}
+
+// ResolveXData copies the fields of the @xdata entries referenced by the xdata field of every
+// entry (see XData) into the entry. Like biblatex, all fields except ids and xdata are
+// inherited; fields of the entry are never overwritten, and if several @xdata entries provide
+// a field, the first one wins. @xdata entries can reference other @xdata entries themselves.
+// ResolveXData returns one ErrMissingXData per key that does not match an entry.
+// As biblatex resolves xdata before crossrefs, it should be called before ResolveCrossrefs
+// and Validate.
+func (f *BibTeXFile) ResolveXData() []error {
+	var errs []error
+	resolved := make(map[*Entry]bool, len(f.Entries))
+	var resolve func(entry *Entry)
+	resolve = func(entry *Entry) {
+		if resolved[entry] {
+			return
+		}
+		// Mark the entry first to stop at cyclic references
+		resolved[entry] = true
+		for _, key := range entry.XData() {
+			xdata, ok := f.LookupByKey(key)
+			if !ok {
+				errs = append(errs, &ErrMissingXData{Key: entry.Key, XData: key})
+				continue
+			}
+			resolve(xdata)
+			entry.copyMissingFields(xdata, nonXDataFields)
+		}
+	}
+	for _, entry := range f.Entries {
+		resolve(entry)
+	}
+	return errs
+}
+
+// copyMissingFields copies the fields of the source that are missing in the entry, except
+// for the excluded fields. The raw field names are copied as well.
+func (e *Entry) copyMissingFields(source *Entry, excluded map[string]bool) {
+	if e.Fields == nil {
+		e.Fields = make(map[string]string, len(source.Fields))
+	}
+	if e.RawFieldNames == nil {
+		e.RawFieldNames = make(map[string]string, len(source.Fields))
+	}
+	for fieldName, value := range source.Fields {
+		if _, ok := e.Fields[fieldName]; ok || excluded[fieldName] {
+			continue
+		}
+		e.Fields[fieldName] = value
+		if rawFieldName, ok := source.RawFieldNames[fieldName]; ok {
+			e.RawFieldNames[fieldName] = rawFieldName
+		}
+	}
+}
+
+// isPrinted reports whether the entry can appear in a bibliography. @xdata entries only
+// provide fields for other entries, so they are never cited or exported.
+func (e *Entry) isPrinted() bool {
+	entryType, _ := e.TypedType()
+	return entryType != EntryXData
+}
+
+// splitKeyList returns the trimmed keys of a comma-separated list of keys (e.g., {a, b}),
+// dropping empty keys. It returns nil for an empty list.
+func splitKeyList(list string) []string {
+	var keys []string
+	for _, key := range strings.Split(list, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
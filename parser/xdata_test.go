@@ -0,0 +1,54 @@
+// Unit-tests for xdata.go
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveXData(t *testing.T) {
+	file, _ := ParseString(`@xdata{lncs, series = {Lecture Notes in Computer Science}, Publisher = {Springer}, xdata = {berlin}}
+@xdata{berlin, location = {Berlin}, publisher = {Other}}
+@inproceedings{doe2020, author = {Jane Doe}, title = {A Paper}, booktitle = {Proceedings}, year = {2020}, xdata = { lncs,, berlin }, location = {Heidelberg}}
+@misc{missing, title = {M}, xdata = {nothing}}`)
+
+	// Case 1: The keys of the xdata field are trimmed and empty keys are dropped
+	entry, _ := file.LookupByKey("doe2020")
+	expectedKeys := []string{"lncs", "berlin"}
+	if keys := entry.XData(); !reflect.DeepEqual(expectedKeys, keys) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedKeys, keys)
+	}
+
+	// Case 2: Missing fields are inherited, also through nested @xdata entries, and the
+	// fields of the entry and the first @xdata entry win
+	expectedErrs := []error{&ErrMissingXData{Key: "missing", XData: "nothing"}}
+	if errs := file.ResolveXData(); !reflect.DeepEqual(expectedErrs, errs) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedErrs, errs)
+	}
+	expected := map[string]string{
+		"author": "Jane Doe", "title": "A Paper", "booktitle": "Proceedings", "year": "2020",
+		"xdata": " lncs,, berlin ", "location": "Heidelberg",
+		"series": "Lecture Notes in Computer Science", "publisher": "Springer",
+	}
+	if !reflect.DeepEqual(expected, entry.Fields) {
+		t.Errorf("Expected '%#v', but got '%#v'", expected, entry.Fields)
+	}
+	if entry.RawFieldName("publisher") != "Publisher" {
+		t.Errorf("Expected '%#v', but got '%#v'", "Publisher", entry.RawFieldName("publisher"))
+	}
+
+	// Case 3: @xdata entries do not require fields and are neither cited nor exported
+	xdata, _ := file.LookupByKey("berlin")
+	if errs := xdata.Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, but got '%#v'", errs)
+	}
+	expectedUnused := []string{"missing"}
+	if _, unused := file.CrossCheckCitations([]string{"doe2020"}); !reflect.DeepEqual(expectedUnused, unused) {
+		t.Errorf("Expected '%#v', but got '%#v'", expectedUnused, unused)
+	}
+	data, _ := file.MarshalRIS()
+	if records := strings.Count(string(data), "ER  - "); records != 2 {
+		t.Errorf("Expected '%#v', but got '%#v'", 2, records)
+	}
+}